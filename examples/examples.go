@@ -2,11 +2,16 @@
 package examples
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"time"
 )
 
-//go:generate go run .. -type=NamedString
+// Types generated without flags share one file.
+//
+//go:generate go run .. -type=NamedString,EmptyStruct,BasicStruct,ComplexStruct,EmbeddedStruct,ShadowStruct,NestingStruct,NumberStruct,Users,Index,Celsius,Stamp,CaseStruct,StrictStruct -output=examples_gen_json.go -fingerprint
+
 type NamedString string
 
 var (
@@ -14,7 +19,6 @@ var (
 	NamedStringJSON  = []byte(`"foo"`)
 )
 
-//go:generate go run .. -type=EmptyStruct
 type EmptyStruct struct{}
 
 var (
@@ -22,12 +26,12 @@ var (
 	EmptyStructJSON  = []byte(`{}`)
 )
 
-//go:generate go run .. -type=BasicStruct
 type BasicStruct struct {
 	Name   string `json:"name"`
 	Age    int    `json:"age"`
 	Email  string `json:"email"`
 	Active bool   `json:"active"`
+	lower  int    // unexported fields are ignored
 }
 
 var (
@@ -47,7 +51,7 @@ var (
 	`))
 )
 
-//go:generate go run .. -type=NestedStruct
+//go:generate go run .. -type=NestedStruct -collect
 type NestedStruct struct {
 	ID      int           `json:"id"`
 	Profile []BasicStruct `json:"profile"`
@@ -78,7 +82,6 @@ var (
 	))
 )
 
-//go:generate go run .. -type=ComplexStruct
 type ComplexStruct struct {
 	ID        int            `json:"id"`
 	Data      map[string]any `json:"data"`
@@ -110,7 +113,7 @@ var (
 			0.0,
 		},
 		Metadata:  &BasicStructValue,
-		CreatedAt: time.Date(2025, 9, 21, 17, 0, 0, 0, time.Local),
+		CreatedAt: time.Date(2025, 9, 21, 15, 0, 0, 0, time.UTC),
 	}
 	ComplexStructJSON = canonicalize(fmt.Appendf(nil, `
 		{
@@ -135,13 +138,12 @@ var (
 		        0
 		    ],
 		    "metadata": %s,
-		    "created_at": "2025-09-21T17:00:00+02:00"
+		    "created_at": "2025-09-21T15:00:00Z"
 		}`,
 		BasicStructJSON,
 	))
 )
 
-//go:generate go run .. -type=EmbeddedStruct
 type EmbeddedStruct struct {
 	BasicStruct `json:",inline"`
 	NestedStruct
@@ -150,17 +152,271 @@ type EmbeddedStruct struct {
 
 var (
 	EmbeddedStructValue = EmbeddedStruct{
-		BasicStruct: BasicStructValue,
-		ExtraField:  "extra",
+		BasicStruct:  BasicStructValue,
+		NestedStruct: NestedStruct{Profile: []BasicStruct{}, Tags: []string{}},
+		ExtraField:   "extra",
 	}
 	EmbeddedStructJSON = canonicalize(join([]byte(`
 		{
+			"id": 0,
+			"profile": [],
+			"tags": [],
 			"extra_field": "extra"
 		}`),
 		BasicStructJSON,
 	))
 )
 
+// ShadowStruct names members like its embedded structs: the shallowest
+// field of a name is used, or the one tagged with it among the shallowest.
+type ShadowStruct struct {
+	BasicStruct
+	TaggedLabel
+	UntaggedLabel
+	Name string `json:"name"`
+}
+
+type TaggedLabel struct {
+	Label string `json:"Label"`
+	Note  string
+}
+
+// UntaggedLabel shares its Note with TaggedLabel, so that neither is used.
+type UntaggedLabel struct {
+	Label string
+	Note  string
+}
+
+var (
+	ShadowStructValue = ShadowStruct{
+		BasicStruct: BasicStruct{Age: 42},
+		TaggedLabel: TaggedLabel{Label: "label"},
+		Name:        "shadow",
+	}
+	ShadowStructJSON = canonicalize([]byte(`
+		{
+			"age": 42,
+			"email": "",
+			"active": false,
+			"Label": "label",
+			"name": "shadow"
+		}
+	`))
+)
+
+type NestingStruct struct {
+	Items  []*struct{ A int }           `json:"items"`
+	Index  *[]map[string][]*BasicStruct `json:"index"`
+	Groups map[string]struct {
+		Names []string `json:"names"`
+	} `json:"groups"`
+}
+
+var (
+	NestingStructValue = NestingStruct{
+		Items: []*struct{ A int }{{A: 1}, nil, {A: 3}},
+		Index: &[]map[string][]*BasicStruct{
+			{"foo": {&BasicStructValue, nil}},
+			{},
+		},
+		Groups: map[string]struct {
+			Names []string `json:"names"`
+		}{
+			"admins": {Names: []string{"foo", "bar"}},
+		},
+	}
+	NestingStructJSON = canonicalize(fmt.Appendf(nil, `
+		{
+			"items": [{"A": 1}, null, {"A": 3}],
+			"index": [
+				{"foo": [%s, null]},
+				{}
+			],
+			"groups": {
+				"admins": {"names": ["foo", "bar"]}
+			}
+		}`,
+		BasicStructJSON,
+	))
+)
+
+type NumberStruct struct {
+	Number json.Number `json:"number"`
+	Amount string      `json:"amount,format:number"`
+	Int    *big.Int    `json:"int"`
+	Float  *big.Float  `json:"float"`
+	Rat    *big.Rat    `json:"rat"`
+}
+
+var (
+	// NumberStructJSON holds numbers that cannot be represented by float64
+	// or int64 without losing digits.
+	NumberStructJSON = []byte(`{` +
+		`"number":12345678901234567890.123456789,` +
+		`"amount":0.1000000000000000055511151231257827,` +
+		`"int":123456789012345678901234567890,` +
+		`"float":3.14159265358979323846264338327950288,` +
+		`"rat":-0.125` +
+		`}`)
+)
+
+type Users []BasicStruct
+
+var (
+	UsersValue = Users{BasicStructValue, {Name: "bar", Age: 7, Active: true}}
+	UsersJSON  = []byte(`[` +
+		`{"name":"foo","age":42,"email":"foo@bar.baz","active":false},` +
+		`{"name":"bar","age":7,"email":"","active":true}` +
+		`]`)
+)
+
+type Index map[string][]int
+
+var (
+	IndexValue = Index{"even": {0, 2, 4}, "odd": {1, 3}, "none": {}}
+	IndexJSON  = []byte(`{"even":[0,2,4],"none":[],"odd":[1,3]}`)
+)
+
+type Celsius float64
+
+var (
+	CelsiusValue = Celsius(-40.5)
+	CelsiusJSON  = []byte(`-40.5`)
+)
+
+type Stamp time.Time
+
+var (
+	StampValue = Stamp(time.Date(2025, 9, 21, 15, 0, 0, 0, time.UTC))
+	StampJSON  = []byte(`"2025-09-21T15:00:00Z"`)
+)
+
+type CaseStruct struct {
+	Name   string `json:"name"`
+	UserID int    `json:"user_id,case:ignore"`
+	Token  string `json:"token,case:strict"`
+	TOKEN  string
+}
+
+var (
+	CaseStructValue = CaseStruct{Name: "foo", UserID: 42, Token: "a", TOKEN: "b"}
+	CaseStructJSON  = []byte(`{"name":"foo","user_id":42,"token":"a","TOKEN":"b"}`)
+)
+
+// LegacyStruct matches member names like encoding/json v1.
+//
+//go:generate go run .. -type=LegacyStruct -ignorecase
+type LegacyStruct struct {
+	UserName  string
+	FirstName string `json:"first_name"`
+	Code      string `json:"code,case:strict"`
+}
+
+var (
+	LegacyStructValue = LegacyStruct{UserName: "foo", FirstName: "bar", Code: "baz"}
+	LegacyStructJSON  = []byte(`{"USERNAME":"foo","First_Name":"bar","code":"baz"}`)
+)
+
+// StrictStruct rejects unknown and duplicate members whatever the options.
+//
+//gogenjson:rejectunknown
+//gogenjson:rejectduplicates
+type StrictStruct struct {
+	Name  string      `json:"name"`
+	Inner BasicStruct `json:"inner"`
+}
+
+var (
+	StrictStructValue = StrictStruct{Name: "foo", Inner: BasicStructValue}
+	StrictStructJSON  = []byte(`{"name":"foo","inner":{"name":"foo","age":42,"email":"foo@bar.baz","active":false}}`)
+)
+
+//go:generate go run .. -type=StrictIndex -rejectunknown -rejectduplicates
+type StrictIndex map[string]BasicStruct
+
+//go:generate go run .. -type=UniqueIndex -rejectduplicates
+type UniqueIndex map[string]BasicStruct
+
+//go:generate go run .. -type=Account -collect
+type Account struct {
+	ID    int    `json:"id,required"`
+	Email string `json:"email,required"`
+	Name  string `json:"name"`
+	Owner *Owner `json:"owner,omitempty"`
+}
+
+// Owner requires all of its members but those tagged omitempty or omitzero.
+//
+//gogenjson:required
+type Owner struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+}
+
+var (
+	AccountValue = Account{ID: 1, Email: "foo@bar.baz", Owner: &Owner{Name: "foo"}}
+	AccountJSON  = []byte(`{"id":1,"email":"foo@bar.baz","name":"","owner":{"name":"foo"}}`)
+)
+
+//go:generate go run .. -type=Payload -maxdepth=4 -maxbytes=1024 -maxelements=8 -maxmembers=8 -maxstring=32
+type Payload struct {
+	Name  string            `json:"name"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+	Grid  [][]int           `json:"grid"`
+	Extra any               `json:"extra"`
+}
+
+var (
+	PayloadValue = Payload{
+		Name:  "foo",
+		Tags:  []string{"a", "b"},
+		Attrs: map[string]string{"k": "v"},
+		Grid:  [][]int{{1, 2}, {3}},
+		Extra: map[string]any{"list": []any{"x", true}},
+	}
+	PayloadJSON = []byte(`{"name":"foo","tags":["a","b"],"attrs":{"k":"v"},"grid":[[1,2],[3]],"extra":{"list":["x",true]}}`)
+)
+
+// Marked types share one file.
+//
+//go:generate go run .. -output=marked_gen_json.go
+
+// Event encodes its maps in sorted key order.
+//
+//json:generate deterministic
+type Event struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+	Data   any               `json:"data"`
+}
+
+var (
+	EventValue = Event{
+		Name:   "foo",
+		Labels: map[string]string{"c": "3", "a": "1", "b": "2"},
+		Data:   map[string]any{"z": 1.0, "y": []any{map[string]any{"k": true, "j": false}}},
+	}
+	EventJSON = []byte(`{"name":"foo","labels":{"a":"1","b":"2","c":"3"},"data":{"y":[{"j":false,"k":true}],"z":1}}`)
+)
+
+// Report is only encoded.
+//
+//gogenjson:codec marshal-only
+type Report struct {
+	Title  string         `json:"title"`
+	Counts map[string]int `json:"counts"`
+}
+
+// Settings rejects unknown members and duplicate names, and reports all
+// errors with UnmarshalJSONCollect.
+//
+//json:generate strict collect
+type Settings struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
 type TaggedStruct struct {
 	PublicField     string `json:"public_field"`
 	PrivateField    string `json:"-"`
@@ -169,10 +425,21 @@ type TaggedStruct struct {
 	unexportedField string
 }
 
+//go:generate go run .. -type=InterfaceStruct -usenumber
 type InterfaceStruct struct {
 	Value any `json:"value"`
 }
 
+var (
+	InterfaceStructValue = InterfaceStruct{
+		Value: map[string]any{
+			"numbers": []any{json.Number("1.5"), json.Number("12345678901234567890")},
+			"text":    "foo",
+		},
+	}
+	InterfaceStructJSON = []byte(`{"value":{"numbers":[1.5,12345678901234567890],"text":"foo"}}`)
+)
+
 type SliceStruct struct {
 	StringSlice []string         `json:"string_slice"`
 	IntSlice    []int            `json:"int_slice"`
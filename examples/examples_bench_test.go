@@ -3,6 +3,7 @@ package examples_test
 import (
 	"encoding/json/v2"
 	"testing"
+	"time"
 
 	"github.com/paskozdilar/go-gen-json/examples"
 )
@@ -41,7 +42,13 @@ func benchMarshal[T, W any](v T, w W) (func(*testing.B), func(*testing.B)) {
 	return fnt, fnw
 }
 
-func bench[T, W any](b *testing.B, in []byte, v T, w W) {
+// bench compares the generated methods of T with json/v2 reflection on the
+// reference type W, which is decoded from in.
+func bench[T, W any](b *testing.B, in []byte, v T) {
+	var w W
+	if err := json.Unmarshal(in, &w); err != nil {
+		b.Fatalf("reference unmarshal error: %v", err)
+	}
 	b.Run("Unmarshal", func(b *testing.B) {
 		if _, ok := any(&v).(json.Unmarshaler); !ok {
 			b.Skipf("type %T does not implement json.Unmarshaler", &v)
@@ -60,32 +67,81 @@ func bench[T, W any](b *testing.B, in []byte, v T, w W) {
 	})
 }
 
+// The reference types are declared structurally, rather than as defined
+// types of the generated ones, so that neither they nor their fields and
+// embedded structs have the generated methods.
+type _BasicStruct struct {
+	Name   string `json:"name"`
+	Age    int    `json:"age"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+}
+
+type _NestedStruct struct {
+	ID      int            `json:"id"`
+	Profile []_BasicStruct `json:"profile"`
+	Tags    []string       `json:"tags"`
+}
+
 func BenchmarkNamedString(b *testing.B) {
 	type _NamedString examples.NamedString
-	bench(b, examples.NamedStringJSON, examples.NamedStringValue, _NamedString(examples.NamedStringValue))
+	bench[examples.NamedString, _NamedString](b, examples.NamedStringJSON, examples.NamedStringValue)
 }
 
 func BenchmarkEmptyStruct(b *testing.B) {
 	type _EmptyStruct examples.EmptyStruct
-	bench(b, examples.EmptyStructJSON, examples.EmptyStructValue, _EmptyStruct(examples.EmptyStructValue))
+	bench[examples.EmptyStruct, _EmptyStruct](b, examples.EmptyStructJSON, examples.EmptyStructValue)
 }
 
 func BenchmarkBasicStruct(b *testing.B) {
-	type _BasicStruct examples.BasicStruct
-	bench(b, examples.BasicStructJSON, examples.BasicStructValue, _BasicStruct(examples.BasicStructValue))
+	bench[examples.BasicStruct, _BasicStruct](b, examples.BasicStructJSON, examples.BasicStructValue)
 }
 
 func BenchmarkNestedStruct(b *testing.B) {
-	type _NestedStruct examples.NestedStruct
-	bench(b, examples.NestedStructJSON, examples.NestedStructValue, _NestedStruct(examples.NestedStructValue))
+	bench[examples.NestedStruct, _NestedStruct](b, examples.NestedStructJSON, examples.NestedStructValue)
 }
 
 func BenchmarkComplexStruct(b *testing.B) {
-	type _ComplexStruct examples.ComplexStruct
-	bench(b, examples.ComplexStructJSON, examples.ComplexStructValue, _ComplexStruct(examples.ComplexStructValue))
+	type _ComplexStruct struct {
+		ID        int            `json:"id"`
+		Data      map[string]any `json:"data"`
+		Numbers   []float64      `json:"numbers"`
+		Metadata  *_BasicStruct  `json:"metadata,omitempty"`
+		CreatedAt time.Time      `json:"created_at"`
+	}
+	bench[examples.ComplexStruct, _ComplexStruct](b, examples.ComplexStructJSON, examples.ComplexStructValue)
 }
 
 func BenchmarkEmbeddedStruct(b *testing.B) {
-	type _EmbeddedStruct examples.EmbeddedStruct
-	bench(b, examples.EmbeddedStructJSON, examples.EmbeddedStructValue, _EmbeddedStruct(examples.EmbeddedStructValue))
+	type _EmbeddedStruct struct {
+		_BasicStruct `json:",inline"`
+		_NestedStruct
+		ExtraField string `json:"extra_field"`
+	}
+	bench[examples.EmbeddedStruct, _EmbeddedStruct](b, examples.EmbeddedStructJSON, examples.EmbeddedStructValue)
+}
+
+func BenchmarkNestingStruct(b *testing.B) {
+	type _NestingStruct struct {
+		Items  []*struct{ A int }            `json:"items"`
+		Index  *[]map[string][]*_BasicStruct `json:"index"`
+		Groups map[string]struct {
+			Names []string `json:"names"`
+		} `json:"groups"`
+	}
+	bench[examples.NestingStruct, _NestingStruct](b, examples.NestingStructJSON, examples.NestingStructValue)
+}
+
+func BenchmarkUsers(b *testing.B) {
+	bench[examples.Users, []_BasicStruct](b, examples.UsersJSON, examples.UsersValue)
+}
+
+func BenchmarkIndex(b *testing.B) {
+	type _Index examples.Index
+	bench[examples.Index, _Index](b, examples.IndexJSON, examples.IndexValue)
+}
+
+func BenchmarkCelsius(b *testing.B) {
+	type _Celsius examples.Celsius
+	bench[examples.Celsius, _Celsius](b, examples.CelsiusJSON, examples.CelsiusValue)
 }
@@ -2,10 +2,19 @@ package examples_test
 
 import (
 	"bytes"
+	jsonv1 "encoding/json"
+	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"math/big"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/paskozdilar/go-gen-json/examples"
 )
@@ -28,16 +37,31 @@ func testUnmarshal[T any](in []byte, out T) func(*testing.T) {
 	}
 }
 
-func testMarshal[T any](v T, out []byte) func(*testing.T) {
+// testMarshal checks that v encodes like out, and exactly like the reference
+// type W, which has the fields of T and no methods.
+func testMarshal[T, W any](v T, out []byte) func(*testing.T) {
+	return testMarshalLike(v, reflect.ValueOf(&v).Convert(reflect.TypeFor[*W]()).Interface(), out)
+}
+
+// testMarshalLike checks that v encodes like out, and exactly like json/v2
+// encodes the reference value ref.
+func testMarshalLike[T any](v T, ref any, out []byte) func(*testing.T) {
 	return func(t *testing.T) {
 		if _, ok := any(&v).(json.Marshaler); !ok {
 			t.Skipf("type %T does not implement json.Marshaler", &v)
 		}
-		b, err := json.Marshal(&v)
+		b, err := json.Marshal(&v, json.Deterministic(true))
 		if err != nil {
 			t.Fatalf("marshal error: %v", err)
 		}
-		if !bytes.Equal(b, out) {
+		want, err := json.Marshal(ref, json.Deterministic(true))
+		if err != nil {
+			t.Fatalf("reference marshal error: %v", err)
+		}
+		if !bytes.Equal(b, want) {
+			t.Errorf("marshal error: differs from json/v2, got: %s, want: %s", b, want)
+		}
+		if !equalJSON(b, out) {
 			log.Fatalf(
 				"marshal error: differes from json/v2, got: %s, want: %s",
 				b, out,
@@ -48,30 +72,727 @@ func testMarshal[T any](v T, out []byte) func(*testing.T) {
 
 func TestNamedString(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.NamedStringJSON, examples.NamedStringValue))
-	t.Run("Marshal", testMarshal(examples.NamedStringValue, examples.NamedStringJSON))
+	type noGenNamedString examples.NamedString
+	t.Run("Marshal", testMarshal[examples.NamedString, noGenNamedString](examples.NamedStringValue, examples.NamedStringJSON))
 }
 
 func TestEmptyStruct(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.EmptyStructJSON, examples.EmptyStructValue))
-	t.Run("Marshal", testMarshal(examples.EmptyStructValue, examples.EmptyStructJSON))
+	type noGenEmptyStruct examples.EmptyStruct
+	t.Run("Marshal", testMarshal[examples.EmptyStruct, noGenEmptyStruct](examples.EmptyStructValue, examples.EmptyStructJSON))
 }
 
 func TestBasicStruct(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.BasicStructJSON, examples.BasicStructValue))
-	t.Run("Marshal", testMarshal(examples.BasicStructValue, examples.BasicStructJSON))
+	type noGenBasicStruct examples.BasicStruct
+	t.Run("Marshal", testMarshal[examples.BasicStruct, noGenBasicStruct](examples.BasicStructValue, examples.BasicStructJSON))
 }
 
 func TestNestedStruct(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.NestedStructJSON, examples.NestedStructValue))
-	t.Run("Marshal", testMarshal(examples.NestedStructValue, examples.NestedStructJSON))
+	type noGenNestedStruct examples.NestedStruct
+	t.Run("Marshal", testMarshal[examples.NestedStruct, noGenNestedStruct](examples.NestedStructValue, examples.NestedStructJSON))
 }
 
 func TestComplexStruct(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.ComplexStructJSON, examples.ComplexStructValue))
-	t.Run("Marshal", testMarshal(examples.ComplexStructValue, examples.ComplexStructJSON))
+	type noGenComplexStruct examples.ComplexStruct
+	t.Run("Marshal", testMarshal[examples.ComplexStruct, noGenComplexStruct](examples.ComplexStructValue, examples.ComplexStructJSON))
 }
 
 func TestEmbeddedStruct(t *testing.T) {
 	t.Run("Unmarshal", testUnmarshal(examples.EmbeddedStructJSON, examples.EmbeddedStructValue))
-	t.Run("Marshal", testMarshal(examples.EmbeddedStructValue, examples.EmbeddedStructJSON))
+	// json/v2 does not inline types with methods: the reference embeds
+	// copies of them without
+	type noGenBasicStruct examples.BasicStruct
+	type noGenNestedStruct examples.NestedStruct
+	type noGenEmbeddedStruct struct {
+		noGenBasicStruct `json:",inline"`
+		noGenNestedStruct
+		ExtraField string `json:"extra_field"`
+	}
+	v := examples.EmbeddedStructValue
+	ref := noGenEmbeddedStruct{noGenBasicStruct(v.BasicStruct), noGenNestedStruct(v.NestedStruct), v.ExtraField}
+	t.Run("Marshal", testMarshalLike(v, &ref, examples.EmbeddedStructJSON))
+}
+
+func TestShadowStruct(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.ShadowStructJSON, examples.ShadowStructValue))
+	type noGenBasicStruct examples.BasicStruct
+	type noGenShadowStruct struct {
+		noGenBasicStruct
+		examples.TaggedLabel
+		examples.UntaggedLabel
+		Name string `json:"name"`
+	}
+	// Shadowed fields are not encoded
+	v := examples.ShadowStructValue
+	v.BasicStruct.Name, v.TaggedLabel.Note, v.UntaggedLabel = "basic", "tagged", examples.UntaggedLabel{Label: "untagged", Note: "untagged"}
+	ref := noGenShadowStruct{noGenBasicStruct(v.BasicStruct), v.TaggedLabel, v.UntaggedLabel, v.Name}
+	t.Run("Marshal", testMarshalLike(v, &ref, examples.ShadowStructJSON))
+
+	// Nor decoded
+	in := []byte(`{"name":"shadow","Label":"label","Note":"note"}`)
+	var got examples.ShadowStruct
+	var want noGenShadowStruct
+	if err := json.Unmarshal(in, &got); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if err := json.Unmarshal(in, &want); err != nil {
+		t.Fatalf("reference unmarshal error: %v", err)
+	}
+	if got.BasicStruct != examples.BasicStruct(want.noGenBasicStruct) || got.TaggedLabel != want.TaggedLabel || got.UntaggedLabel != want.UntaggedLabel || got.Name != want.Name {
+		t.Errorf("unmarshal error: differs from json/v2, got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestNestingStruct(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.NestingStructJSON, examples.NestingStructValue))
+	type noGenNestingStruct examples.NestingStruct
+	t.Run("Marshal", testMarshal[examples.NestingStruct, noGenNestingStruct](examples.NestingStructValue, examples.NestingStructJSON))
+}
+
+func TestInterfaceStruct(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.InterfaceStructJSON, examples.InterfaceStructValue))
+	type noGenInterfaceStruct examples.InterfaceStruct
+	t.Run("Marshal", testMarshal[examples.InterfaceStruct, noGenInterfaceStruct](examples.InterfaceStructValue, examples.InterfaceStructJSON))
+}
+
+func TestUsers(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.UsersJSON, examples.UsersValue))
+	type noGenUsers examples.Users
+	t.Run("Marshal", testMarshal[examples.Users, noGenUsers](examples.UsersValue, examples.UsersJSON))
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.IndexJSON, examples.IndexValue))
+	type noGenIndex examples.Index
+	t.Run("Marshal", testMarshal[examples.Index, noGenIndex](examples.IndexValue, examples.IndexJSON))
+}
+
+func TestCelsius(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.CelsiusJSON, examples.CelsiusValue))
+	type noGenCelsius examples.Celsius
+	t.Run("Marshal", testMarshal[examples.Celsius, noGenCelsius](examples.CelsiusValue, examples.CelsiusJSON))
+}
+
+func TestStamp(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.StampJSON, examples.StampValue))
+	// Stamp encodes like the time.Time it is declared as
+	t.Run("Marshal", testMarshal[examples.Stamp, time.Time](examples.StampValue, examples.StampJSON))
+}
+
+func TestCaseStruct(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.CaseStructJSON, examples.CaseStructValue))
+	type noGenCaseStruct examples.CaseStruct
+	t.Run("Marshal", testMarshal[examples.CaseStruct, noGenCaseStruct](examples.CaseStructValue, examples.CaseStructJSON))
+	for _, in := range []string{
+		`{"NAME":"foo","UserId":1}`,
+		`{"USER-ID":2}`,
+		`{"Token":"a","token":"b"}`,
+		`{"tOKEN":"a","name":"b"}`,
+		`{"UserId":1,"USER-ID":2}`,
+		`{"Name":"foo","name":"bar"}`,
+		`{"tOKEN":"a","TOKEN":"b"}`,
+	} {
+		testOptions[examples.CaseStruct, noGenCaseStruct](t, in, jsontext.AllowDuplicateNames(true))
+		testOptions[examples.CaseStruct, noGenCaseStruct](t, in, json.MatchCaseInsensitiveNames(true), jsontext.AllowDuplicateNames(true))
+		testOptions[examples.CaseStruct, noGenCaseStruct](t, in)
+		testOptions[examples.CaseStruct, noGenCaseStruct](t, in, json.MatchCaseInsensitiveNames(true))
+	}
+}
+
+func TestStrictStruct(t *testing.T) {
+	skipFallback(t, "strictness limited to a type")
+	t.Run("Unmarshal", testUnmarshal(examples.StrictStructJSON, examples.StrictStructValue))
+	type noGenStrictStruct examples.StrictStruct
+	t.Run("Marshal", testMarshal[examples.StrictStruct, noGenStrictStruct](examples.StrictStructValue, examples.StrictStructJSON))
+	for _, in := range []string{
+		`{"name":"foo","name":"bar"}`,
+		`{"unknown":1}`,
+		`{"inner":{"age":1,"age":2}}`,
+		`{"inner":{"unknown":1}}`,
+	} {
+		// Strictness of the directives does not extend to BasicStruct
+		var v examples.StrictStruct
+		var w noGenStrictStruct
+		err := json.Unmarshal([]byte(in), &v, jsontext.AllowDuplicateNames(true))
+		want := json.Unmarshal([]byte(in), &w, json.RejectUnknownMembers(true))
+		if strings.HasPrefix(in, `{"inner"`) {
+			want = json.Unmarshal([]byte(in), &w, jsontext.AllowDuplicateNames(true))
+		}
+		if err != nil || want != nil {
+			testSemanticError(t, in, err, want)
+		}
+	}
+}
+
+func TestStrictIndex(t *testing.T) {
+	type noGenStrictIndex examples.StrictIndex
+	for _, in := range []string{
+		`{"a":{"name":"foo","name":"bar"}}`,
+		`{"a":{"unknown":1}}`,
+		`{"a":{"age":1},"b":{"Age":1}}`,
+		`{"a":{"name":"x"},"a":{"age":1}}`,
+	} {
+		var v examples.StrictIndex
+		var w noGenStrictIndex
+		err := json.Unmarshal([]byte(in), &v, jsontext.AllowDuplicateNames(true))
+		want := json.Unmarshal([]byte(in), &w, json.RejectUnknownMembers(true))
+		testSemanticError(t, in, err, want)
+	}
+}
+
+func TestUniqueIndex(t *testing.T) {
+	type noGenUniqueIndex examples.UniqueIndex
+	for _, in := range []string{
+		`{"a":{"name":"x"},"a":{"age":1}}`,
+		`{"a":{"unknown":1,"unknown":2}}`,
+		`{"a":{"unknown":1},"b":{"unknown":2}}`,
+	} {
+		// Unknown members are skipped, but not duplicated
+		var v examples.UniqueIndex
+		var w noGenUniqueIndex
+		err := json.Unmarshal([]byte(in), &v, jsontext.AllowDuplicateNames(true))
+		want := json.Unmarshal([]byte(in), &w)
+		if err != nil || want != nil {
+			testSemanticError(t, in, err, want)
+		}
+	}
+}
+
+func TestAccount(t *testing.T) {
+	skipFallback(t, "required members")
+	t.Run("Unmarshal", testUnmarshal(examples.AccountJSON, examples.AccountValue))
+	type noGenAccount examples.Account
+	t.Run("Marshal", testMarshal[examples.Account, noGenAccount](examples.AccountValue, examples.AccountJSON))
+	for _, c := range []struct{ in, want string }{
+		{`{"name":"foo"}`, `json: cannot unmarshal JSON object into Go examples.Account after offset 13: missing required members "id", "email"`},
+		{`{"id":1,"email":""}`, ``},
+		{`{"id":1,"email":"","owner":{"phone":"1"}}`, `json: cannot unmarshal JSON object into Go examples.Owner within "/owner": missing required member "name"`},
+	} {
+		var v examples.Account
+		err := json.Unmarshal([]byte(c.in), &v)
+		if got := errorText(err); c.want == "" && err != nil || c.want != "" && got != c.want {
+			t.Errorf("%s: got error %v, want %q", c.in, err, c.want)
+		}
+	}
+}
+
+func TestPayload(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.PayloadJSON, examples.PayloadValue))
+	type noGenPayload examples.Payload
+	t.Run("Marshal", testMarshal[examples.Payload, noGenPayload](examples.PayloadValue, examples.PayloadJSON))
+	long := strings.Repeat("x", 33)
+	row := "[" + strings.Repeat("100000000000000,", 7) + "100000000000000]"
+	big := `{"grid":[` + strings.Repeat(row+",", 7) + row + `]}`
+	for _, c := range []struct{ in, want string }{
+		{`{"grid":[[1,2,3,4,5,6,7,8]]}`, ``},
+		{`{"grid":[[1,2,3,4,5,6,7,8,9]]}`, `json: cannot unmarshal into Go []int within "/grid/0/7": limit exceeded: more than 8 elements in array`},
+		{`{"grid":[[[]]]}`, `json: cannot unmarshal JSON array into Go int within "/grid/0/0"`},
+		{`{"extra":[[[1]]]}`, ``},
+		{`{"extra":[[[[1]]]]}`, `json: cannot unmarshal into Go interface {} within "/extra/0/0/0": limit exceeded: nesting deeper than 4`},
+		{`{"attrs":{"a":"","b":"","c":"","d":"","e":"","f":"","g":"","h":"","i":""}}`, `json: cannot unmarshal into Go map[string]string within "/attrs/h": limit exceeded: more than 8 members in object`},
+		{`{"name":"` + long + `"}`, `json: cannot unmarshal into Go string within "/name": limit exceeded: string longer than 32 bytes`},
+		{`{"` + long + `":1}`, `json: cannot unmarshal into Go examples.Payload within "/` + long + `": limit exceeded: string longer than 32 bytes`},
+		{`{"extra":{"k":"` + long + `"}}`, `json: cannot unmarshal into Go interface {} within "/extra/k": limit exceeded: string longer than 32 bytes`},
+		{big, `json: cannot unmarshal into Go []int within "/grid/7/6": limit exceeded: more than 1024 bytes of input`},
+	} {
+		var v examples.Payload
+		err := json.Unmarshal([]byte(c.in), &v)
+		if got := errorText(err); c.want == "" && err != nil || c.want != "" && got != c.want {
+			t.Errorf("%s: got error %v, want %q", c.in, err, c.want)
+		}
+	}
+
+	// Values decoded into an existing value are limited alike
+	for _, in := range []string{
+		`{"extra":{"a":[[[1]]]}}`,
+		`{"extra":{"a":"","b":"","c":"","d":"","e":"","f":"","g":"","h":"","i":""}}`,
+		`{"extra":{"` + long + `":1}}`,
+		`{"extra":{"k":"` + long + `"}}`,
+		`{"extra":{"l":[1,2,3,4,5,6,7,8,9]}}`,
+	} {
+		var fresh examples.Payload
+		merged := examples.Payload{Extra: map[string]any{"k": "v"}}
+		var got, want *json.SemanticError
+		if !errors.As(json.Unmarshal([]byte(in), &fresh), &want) {
+			t.Fatalf("%s: got no semantic error decoding into a new value", in)
+		}
+		err := json.Unmarshal([]byte(in), &merged)
+		if !errors.As(err, &got) || got.JSONPointer != want.JSONPointer || got.Err.Error() != want.Err.Error() {
+			t.Errorf("%s: got error %v decoding into an existing value, want %v", in, err, want)
+		}
+	}
+
+	// Depth is counted from where the value starts
+	var nested [][]examples.Payload
+	if err := json.Unmarshal([]byte(`[[{"extra":[[[1]]]}]]`), &nested); err != nil {
+		t.Errorf("nested: unmarshal error: %v", err)
+	}
+	err := json.Unmarshal([]byte(`[[{"extra":[[[[1]]]]}]]`), &nested)
+	if want := `json: cannot unmarshal into Go interface {} within "/0/0/extra/0/0/0": limit exceeded: nesting deeper than 4`; errorText(err) != want {
+		t.Errorf("nested: got error %v, want %q", err, want)
+	}
+
+	// Limits set per call replace the default ones
+	in := []byte(`{"tags":["a","b","c"],"extra":[[[[1]]]]}`)
+	var v examples.Payload
+	if err := v.UnmarshalJSONLimits(in, examples.JSONLimits{}); err != nil {
+		t.Errorf("unlimited: unmarshal error: %v", err)
+	}
+	err = v.UnmarshalJSONLimits(in, examples.JSONLimits{MaxElements: 2})
+	var serr *json.SemanticError
+	if !errors.As(err, &serr) || serr.JSONPointer != "/tags/1" {
+		t.Errorf("got error %v, want a *json.SemanticError at /tags/1", err)
+	}
+	if want := `limit exceeded: more than 2 elements in array`; serr != nil && serr.Err.Error() != want {
+		t.Errorf("got error %q, want %q", serr.Err, want)
+	}
+	// Whole input is rejected before decoding
+	err = v.UnmarshalJSON([]byte(big))
+	if want := `json: cannot unmarshal into Go examples.Payload: limit exceeded: more than 1024 bytes of input`; errorText(err) != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	t.Run("Unmarshal", testUnmarshal(examples.EventJSON, examples.EventValue))
+	// Sorted without json.Deterministic
+	for range 10 {
+		b, err := json.Marshal(&examples.EventValue)
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+		if string(b) != string(examples.EventJSON) {
+			t.Fatalf("got %s, want %s", b, examples.EventJSON)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	v := examples.Report{Title: "foo", Counts: map[string]int{"a": 1}}
+	type noGenReport examples.Report
+	t.Run("Marshal", testMarshal[examples.Report, noGenReport](v, []byte(`{"title":"foo","counts":{"a":1}}`)))
+	if _, ok := any(&v).(json.UnmarshalerFrom); ok {
+		t.Errorf("marshal-only type %T implements json.UnmarshalerFrom", &v)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	skipFallback(t, "collecting errors")
+	// Rejected even when options allow them
+	for _, c := range []struct {
+		in   string
+		want error
+	}{
+		{`{"host":"foo","port":80}`, nil},
+		{`{"host":"foo","user":"bar"}`, json.ErrUnknownName},
+		{`{"host":"foo","host":"bar"}`, jsontext.ErrDuplicateName},
+	} {
+		var v examples.Settings
+		err := json.Unmarshal([]byte(c.in), &v, jsontext.AllowDuplicateNames(true))
+		if c.want == nil && err != nil || !errors.Is(err, c.want) {
+			t.Errorf("%s: got error %v, want %v", c.in, err, c.want)
+		}
+	}
+	var v examples.Settings
+	err := v.UnmarshalJSONCollect([]byte(`{"host":1,"port":"80"}`))
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+		t.Errorf("got %d errors, want 2: %v", n, err)
+	}
+}
+
+func TestUnmarshalJSONCollect(t *testing.T) {
+	skipFallback(t, "collecting errors")
+	for _, c := range []struct {
+		in   string
+		want []string
+	}{
+		{string(examples.AccountJSON), nil},
+		{`{"id":"1","email":2,"name":"foo","owner":{"name":[3],"phone":{"a":4}}}`, []string{
+			`line 1, column 7: json: cannot unmarshal JSON string into Go int within "/id"`,
+			`line 1, column 19: json: cannot unmarshal JSON number into Go string within "/email"`,
+			`line 1, column 50: json: cannot unmarshal JSON array into Go string within "/owner/name"`,
+			`line 1, column 62: json: cannot unmarshal JSON object into Go string within "/owner/phone"`,
+		}},
+		{`{"owner":{}}`, []string{
+			`line 1, column 11: json: cannot unmarshal JSON object into Go examples.Owner within "/owner": missing required member "name"`,
+			`line 1, column 12: json: cannot unmarshal JSON object into Go examples.Account after offset 11: missing required members "id", "email"`,
+		}},
+		{`{"id":1,"email":"","name":1,`, []string{
+			`line 1, column 27: json: cannot unmarshal JSON number into Go string within "/name"`,
+			`line 1, column 29: jsontext: unexpected EOF after offset 28`,
+		}},
+	} {
+		var v examples.Account
+		err := v.UnmarshalJSONCollect([]byte(c.in))
+		var got []string
+		if err != nil {
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				got = append(got, errorText(err))
+			}
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: got errors:\n%s\nwant:\n%s", c.in, strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+		}
+	}
+	var v examples.Account
+	err := v.UnmarshalJSONCollect([]byte(`{"id":"1","name":"foo"}`))
+	if want := (examples.Account{Name: "foo"}); v != want {
+		t.Errorf("got %+v, want %+v", v, want)
+	}
+	var serr *json.SemanticError
+	if !errors.As(err, &serr) || serr.JSONPointer != "/id" || serr.ByteOffset != int64(len(`{"id":`)) {
+		t.Errorf("got error %#v, want a *json.SemanticError at /id", err)
+	}
+}
+
+func TestPositionError(t *testing.T) {
+	account := []byte("{\n\t\"id\": 1,\n\t\"email\": \"foo@bar.baz\",\n\t\"owner\": {\"name\": 42}\n}\n")
+	payload := []byte("{\n\t\"name\": 1\n}\n")
+	for _, c := range []struct {
+		name      string
+		unmarshal func([]byte) error
+		in        []byte
+		want      string
+	}{
+		{"UnmarshalJSON", new(examples.Account).UnmarshalJSON, account, `line 4, column 20: json: cannot unmarshal JSON number into Go string within "/owner/name"`},
+		{"UnmarshalJSONCollect", new(examples.Account).UnmarshalJSONCollect, account, `line 4, column 20: json: cannot unmarshal JSON number into Go string within "/owner/name"`},
+		{"UnmarshalJSONLimits", func(b []byte) error {
+			return new(examples.Payload).UnmarshalJSONLimits(b, examples.JSONLimits{})
+		}, payload, `line 2, column 10: json: cannot unmarshal JSON number into Go string within "/name"`},
+	} {
+		err := c.unmarshal(c.in)
+		if errorText(err) != c.want {
+			t.Errorf("%s: got error %v, want %q", c.name, err, c.want)
+		}
+		var serr *json.SemanticError
+		if !errors.As(err, &serr) {
+			t.Errorf("%s: got error %#v, want a *json.SemanticError", c.name, err)
+		}
+	}
+	err := new(examples.Account).UnmarshalJSON([]byte("{\n\t\"id\": 1\n\t\"email\": \"\"\n}"))
+	var synErr *jsontext.SyntacticError
+	if !errors.As(err, &synErr) || !strings.HasPrefix(err.Error(), "line 3, column 2: jsontext: ") {
+		t.Errorf("got error %v, want a *jsontext.SyntacticError at line 3, column 2", err)
+	}
+}
+
+func TestLegacyStruct(t *testing.T) {
+	type noGenLegacyStruct examples.LegacyStruct
+	for _, in := range []string{
+		string(examples.LegacyStructJSON),
+		`{"username":"foo","firstname":"bar","CODE":"baz"}`,
+		`{"user_name":"foo","FIRST_NAME":"bar","Kode":"baz"}`,
+	} {
+		var got examples.LegacyStruct
+		var want noGenLegacyStruct
+		if err := json.Unmarshal([]byte(in), &got); err != nil {
+			t.Fatalf("%s: unmarshal error: %v", in, err)
+		}
+		if err := jsonv1.Unmarshal([]byte(in), &want); err != nil {
+			t.Fatalf("%s: reference unmarshal error: %v", in, err)
+		}
+		if got != examples.LegacyStruct(want) {
+			t.Errorf("%s: got %+v, want %+v", in, got, want)
+		}
+	}
+}
+
+func TestNumberStruct(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		var v examples.NumberStruct
+		if err := json.Unmarshal(examples.NumberStructJSON, &v); err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		for _, c := range []struct{ got, want string }{
+			{string(v.Number), "12345678901234567890.123456789"},
+			{v.Amount, "0.1000000000000000055511151231257827"},
+			{v.Int.String(), "123456789012345678901234567890"},
+			{v.Float.Text('g', -1), "3.14159265358979323846264338327950288"},
+			{v.Rat.RatString(), "-1/8"},
+		} {
+			if c.got != c.want {
+				t.Errorf("unmarshal error: got %s, want %s", c.got, c.want)
+			}
+		}
+	})
+	t.Run("Marshal", func(t *testing.T) {
+		var v examples.NumberStruct
+		if err := json.Unmarshal(examples.NumberStructJSON, &v); err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		b, err := json.Marshal(&v)
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+		if string(b) != string(examples.NumberStructJSON) {
+			t.Errorf("marshal error: got %s, want %s", b, examples.NumberStructJSON)
+		}
+	})
+	t.Run("QuotedNumber", func(t *testing.T) {
+		var v examples.NumberStruct
+		if err := json.Unmarshal([]byte(`{"number":"-1.5e3"}`), &v); err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		if v.Number != "-1.5e3" {
+			t.Errorf("unmarshal error: got %s, want -1.5e3", v.Number)
+		}
+	})
+	t.Run("InvalidNumber", func(t *testing.T) {
+		for _, in := range []string{
+			`{"number":"1.5x"}`,
+			`{"number":" 1"}`,
+			`{"amount":"01"}`,
+			`{"amount":true}`,
+			`{"int":1.5}`,
+			`{"rat":"1"}`,
+		} {
+			var v examples.NumberStruct
+			if err := json.Unmarshal([]byte(in), &v); err == nil {
+				t.Errorf("unmarshal %s: expected error", in)
+			}
+		}
+		for _, v := range []examples.NumberStruct{
+			{Number: "1.5x"},
+			{Amount: "NaN"},
+			{Rat: big.NewRat(1, 3)},
+		} {
+			if b, err := json.Marshal(&v); err == nil {
+				t.Errorf("marshal %s: expected error", b)
+			}
+		}
+	})
+}
+
+func TestComplexStructData(t *testing.T) {
+	in := []byte(`{"data":{"list":[1,"two",true,false,null,[],{}],"nested":{"a":{"b":[0.5]}},"null":null}}`)
+	want := map[string]any{
+		"list":   []any{1.0, "two", true, false, nil, []any{}, map[string]any{}},
+		"nested": map[string]any{"a": map[string]any{"b": []any{0.5}}},
+		"null":   nil,
+	}
+	var v examples.ComplexStruct
+	if err := json.Unmarshal(in, &v); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(v.Data, want) {
+		t.Fatalf("unmarshal error: got %v, want %v", svaluef(v.Data), svaluef(want))
+	}
+	b, err := json.Marshal(&v)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var w examples.ComplexStruct
+	if err := json.Unmarshal(b, &w); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(w.Data, want) {
+		t.Fatalf("marshal error: got %v, want %v", svaluef(w.Data), svaluef(want))
+	}
+}
+
+// testSemanticError checks that err, returned by generated code, reports the
+// same message, JSON pointer and byte offset as want, returned by json/v2.
+func testSemanticError(t *testing.T, name string, err, want error) {
+	t.Helper()
+	if err == nil || want == nil {
+		t.Errorf("%s: got error %v, want %v", name, err, want)
+		return
+	}
+	// Reference types mirror the generated ones under a noGen prefix, and
+	// the types of the fallback files under a jsonFallback one
+	types := strings.NewReplacer("examples_test.noGen", "examples.", "examples.jsonFallback", "examples.")
+	if types.Replace(err.Error()) != types.Replace(want.Error()) {
+		t.Errorf("%s: got error %q, want %q", name, err, want)
+	}
+	var got, exp *json.SemanticError
+	if gotOK, expOK := errors.As(err, &got), errors.As(want, &exp); gotOK != expOK {
+		t.Errorf("%s: got error %T, want %T", name, err, want)
+		return
+	} else if !gotOK {
+		return
+	}
+	if got.JSONPointer != exp.JSONPointer || got.ByteOffset != exp.ByteOffset ||
+		got.JSONKind != exp.JSONKind || string(got.JSONValue) != string(exp.JSONValue) {
+		t.Errorf("%s: got %s at offset %d (kind %v, value %s), want %s at offset %d (kind %v, value %s)", name,
+			got.JSONPointer, got.ByteOffset, got.JSONKind, got.JSONValue,
+			exp.JSONPointer, exp.ByteOffset, exp.JSONKind, exp.JSONValue)
+	}
+}
+
+func TestSemanticErrors(t *testing.T) {
+	type noGenNestedStruct examples.NestedStruct
+	for _, in := range []string{
+		`{"id":"1"}`,
+		`{"id":1.5}`,
+		`{"id":1e100}`,
+		`{"id": 99999999999999999999}`,
+		`{"tags":[1]}`,
+		`{"tags":{}}`,
+		`{"profile":[{"age":"x"}]}`,
+		`{"profile":[{"name":"ok"}, {"active":"yes"}]}`,
+		`{"profile":[{"name":true}]}`,
+	} {
+		var v examples.NestedStruct
+		var w noGenNestedStruct
+		testSemanticError(t, in, json.Unmarshal([]byte(in), &v), json.Unmarshal([]byte(in), &w))
+	}
+	type noGenComplexStruct examples.ComplexStruct
+	for _, in := range []string{
+		`{"numbers":[1,"2"]}`,
+		`{"data":[]}`,
+		`{"created_at":"yesterday"}`,
+		`{"created_at":0}`,
+		`{"metadata":{"age":false}}`,
+	} {
+		var v examples.ComplexStruct
+		var w noGenComplexStruct
+		testSemanticError(t, in, json.Unmarshal([]byte(in), &v), json.Unmarshal([]byte(in), &w))
+	}
+	for _, v := range []examples.ComplexStruct{
+		{Numbers: []float64{1, math.NaN()}},
+		{Numbers: []float64{math.Inf(-1)}},
+		{Data: map[string]any{"x": math.Inf(1)}},
+	} {
+		_, err := json.Marshal(&v)
+		_, want := json.Marshal(noGenComplexStruct(v))
+		testSemanticError(t, fmt.Sprint(v), err, want)
+	}
+}
+
+// testOptions checks that unmarshaling in and marshaling the result with opts
+// give the same results for T, which has generated methods, as for W, which
+// has the same underlying type but no methods.
+func testOptions[T, W any](t *testing.T, in string, opts ...json.Options) {
+	t.Helper()
+	var v T
+	var w W
+	err, want := json.Unmarshal([]byte(in), &v, opts...), json.Unmarshal([]byte(in), &w, opts...)
+	if err != nil || want != nil {
+		testSemanticError(t, in, err, want)
+		return
+	}
+	got, err := json.Marshal(&v, opts...)
+	if err != nil {
+		t.Errorf("%s: marshal error: %v", in, err)
+		return
+	}
+	exp, err := json.Marshal(&w, opts...)
+	if err != nil {
+		t.Errorf("%s: reference marshal error: %v", in, err)
+		return
+	}
+	if deterministic, _ := json.GetOption(json.JoinOptions(opts...), json.Deterministic); deterministic {
+		if string(got) != string(exp) {
+			t.Errorf("%s: got %s, want %s", in, got, exp)
+		}
+	} else if !equalJSON(got, exp) {
+		t.Errorf("%s: got %s, want %s", in, got, exp)
+	}
+}
+
+// testMerge checks that unmarshaling in onto a T holding init gives the same
+// result as onto a W without generated methods.
+func testMerge[T, W any](t *testing.T, init, in string, opts ...json.Options) {
+	t.Helper()
+	var v T
+	var w W
+	if err := json.Unmarshal([]byte(init), &v); err != nil {
+		t.Fatalf("%s: unmarshal error: %v", init, err)
+	}
+	if err := json.Unmarshal([]byte(init), &w); err != nil {
+		t.Fatalf("%s: reference unmarshal error: %v", init, err)
+	}
+	err, want := json.Unmarshal([]byte(in), &v, opts...), json.Unmarshal([]byte(in), &w, opts...)
+	if err != nil || want != nil {
+		testSemanticError(t, in, err, want)
+		return
+	}
+	got, _ := json.Marshal(&v)
+	exp, _ := json.Marshal(&w)
+	if !equalJSON(got, exp) {
+		t.Errorf("%s onto %s: got %s, want %s", in, init, got, exp)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	type noGenComplexStruct examples.ComplexStruct
+	complexStruct := testMerge[examples.ComplexStruct, noGenComplexStruct]
+	type noGenNestedStruct examples.NestedStruct
+	nestedStruct := testMerge[examples.NestedStruct, noGenNestedStruct]
+	type noGenIndex examples.Index
+	index := testMerge[examples.Index, noGenIndex]
+	type noGenInterfaceStruct examples.InterfaceStruct
+	interfaceStruct := testMerge[examples.InterfaceStruct, noGenInterfaceStruct]
+
+	for _, opts := range [][]json.Options{nil, {jsonv1.MergeWithLegacySemantics(true)}} {
+		t.Run(fmt.Sprint("legacy=", opts != nil), func(t *testing.T) {
+			const complexInit = `{"id":1,"data":{"a":{"b":1},"c":[1]},"numbers":[1,2],"metadata":{"name":"foo","age":1},"created_at":"2025-09-21T15:00:00Z"}`
+			complexStruct(t, complexInit, `{"data":{"a":{"d":2},"c":{"e":3}},"numbers":[3],"metadata":{"age":2}}`, opts...)
+			complexStruct(t, complexInit, `{"id":null,"data":null,"numbers":null,"metadata":null,"created_at":null}`, opts...)
+			complexStruct(t, complexInit, `null`, opts...)
+			complexStruct(t, complexInit, `{"data":{"a":[1]}}`, opts...)
+			nestedStruct(t, `{"id":1,"profile":[{"name":"foo","age":1},{"name":"bar"}],"tags":["a","b"]}`, `{"profile":[{"age":2}],"tags":[]}`, opts...)
+			nestedStruct(t, `{"profile":[{"name":"foo","age":1}]}`, `{"profile":[null,{"age":2}]}`, opts...)
+			index(t, `{"a":[1,2],"b":[3]}`, `{"a":[9],"c":[],"b":null}`, opts...)
+			interfaceStruct(t, `{"value":{"a":1,"b":{"c":2}}}`, `{"value":{"b":{"d":3},"e":4}}`, opts...)
+			interfaceStruct(t, `{"value":"foo"}`, `{"value":1}`, opts...)
+			interfaceStruct(t, `{"value":[1]}`, `{"value":null}`, opts...)
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	type noGenComplexStruct examples.ComplexStruct
+	complexStruct := testOptions[examples.ComplexStruct, noGenComplexStruct]
+	type noGenNestedStruct examples.NestedStruct
+	nestedStruct := testOptions[examples.NestedStruct, noGenNestedStruct]
+	type noGenIndex examples.Index
+	index := testOptions[examples.Index, noGenIndex]
+
+	t.Run("RejectUnknownMembers", func(t *testing.T) {
+		for _, in := range []string{`{"id":1}`, `{"unknown":1}`, `{"profile":[{"age":1,"unknown":[]}]}`} {
+			nestedStruct(t, in, json.RejectUnknownMembers(true))
+			nestedStruct(t, in, json.RejectUnknownMembers(false))
+		}
+	})
+	t.Run("MatchCaseInsensitiveNames", func(t *testing.T) {
+		for _, in := range []string{
+			`{"ID":1,"Tags":["a"],"PROFILE":[{"Name":"foo","AGE":1}]}`,
+			`{"I_D":1,"t-a-g-s":["a"]}`,
+		} {
+			nestedStruct(t, in, json.MatchCaseInsensitiveNames(true))
+			nestedStruct(t, in, json.MatchCaseInsensitiveNames(false))
+		}
+		complexStruct(t, `{"CreatedAt":"2025-09-21T15:00:00Z","Data":{"Id":1}}`, json.MatchCaseInsensitiveNames(true))
+	})
+	t.Run("StringifyNumbers", func(t *testing.T) {
+		for _, in := range []string{`{"id":"42"}`, `{"id":42}`, `{"numbers":["1.5","-2e3"]}`, `{"numbers":[" 1"]}`, `{"id":"0x10"}`} {
+			complexStruct(t, in, json.StringifyNumbers(true))
+		}
+		index(t, `{"a":["1","2"]}`, json.StringifyNumbers(true))
+	})
+	t.Run("FormatNilSliceAsNull", func(t *testing.T) {
+		nestedStruct(t, `{"id":1}`, json.FormatNilSliceAsNull(true))
+		nestedStruct(t, `{"tags":[]}`, json.FormatNilSliceAsNull(true))
+	})
+	t.Run("OmitZeroStructFields", func(t *testing.T) {
+		nestedStruct(t, `{}`, json.OmitZeroStructFields(true))
+		nestedStruct(t, `{"profile":[{},{"age":1}],"tags":[]}`, json.OmitZeroStructFields(true))
+		complexStruct(t, `{"numbers":[0],"metadata":{}}`, json.OmitZeroStructFields(true))
+	})
+	t.Run("Deterministic", func(t *testing.T) {
+		complexStruct(t, `{"data":{"b":1,"a":{"z":[],"y":{"d":0,"c":0}},"c":null}}`, json.Deterministic(true))
+		index(t, `{"z":[1],"y":[2],"x":[3],"w":[]}`, json.Deterministic(true))
+	})
+	t.Run("AllowDuplicateNames", func(t *testing.T) {
+		for _, in := range []string{`{"id":1,"id":2}`, `{"profile":[{"age":1,"age":2}]}`} {
+			nestedStruct(t, in, jsontext.AllowDuplicateNames(true))
+			nestedStruct(t, in, jsontext.AllowDuplicateNames(false))
+		}
+		index(t, `{"a":[1],"a":[2]}`, jsontext.AllowDuplicateNames(true))
+		index(t, `{"a":[1],"a":[2]}`, jsontext.AllowDuplicateNames(false))
+	})
 }
@@ -0,0 +1,12 @@
+//go:build nogenjson
+
+package examples_test
+
+import "testing"
+
+// skipFallback skips a test of a feature of the generated code the fallback
+// files lack, which generating them warns about.
+func skipFallback(t *testing.T, feature string) {
+	t.Helper()
+	t.Skipf("fallback files do not support %s", feature)
+}
@@ -1,15 +1,26 @@
 // Code generated by go-gen-json. DO NOT EDIT.
+
+//go:build !nogenjson
+
 package examples
 
 import (
 	"bytes"
+	"cmp"
+	jsonv1 "encoding/json"
 	"encoding/json/jsontext"
+	"encoding/json/v2"
 	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 func (p *NestedStruct) UnmarshalJSON(b []byte) error {
-	d := jsontext.NewDecoder(bytes.NewReader(b))
-	return p.UnmarshalJSONFrom(d)
+	return positionErrorNestedStruct(b, json.Unmarshal(b, p))
 }
 
 func (p *NestedStruct) UnmarshalJSONFrom(d *jsontext.Decoder) error {
@@ -17,128 +28,991 @@ func (p *NestedStruct) UnmarshalJSONFrom(d *jsontext.Decoder) error {
 		t   jsontext.Token
 		err error
 	)
-	t, err = d.ReadToken()
-	if err != nil {
-		return err
-	}
-	if t.Kind() != '{' {
-		return errors.New("expected object start, got " + string(t.Kind()))
-	}
-	for d.PeekKind() != '}' {
+	opts := d.Options()
+	matchCaseInsensitiveNames, _ := json.GetOption(opts, json.MatchCaseInsensitiveNames)
+	stringifyNumbers, _ := json.GetOption(opts, json.StringifyNumbers)
+	mergeWithLegacySemantics, _ := json.GetOption(opts, jsonv1.MergeWithLegacySemantics)
+	if d.PeekKind() == 'n' {
+		if _, err = d.ReadToken(); err != nil {
+			return err
+		}
+		if !mergeWithLegacySemantics {
+			(*p) = NestedStruct{}
+		}
+	} else {
 		t, err = d.ReadToken()
 		if err != nil {
 			return err
 		}
-		if t.Kind() != '"' {
-			return errors.New("expected string, got " + string(t.Kind()))
+		if t.Kind() != '{' {
+			return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p)).Elem(), nil)
 		}
-		switch t.String() {
-		case "id":
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != '0' {
-				return errors.New("expected number, got " + string(t.Kind()))
-			}
-			(*p).ID = int(t.Int())
-		case "profile":
+		var seen uint64
+		for d.PeekKind() != '}' {
 			t, err = d.ReadToken()
 			if err != nil {
 				return err
 			}
-			if t.Kind() != '[' {
-				return errors.New("expected array start, got " + string(t.Kind()))
-			}
-			(*p).Profile = nil
-			for d.PeekKind() != ']' {
-				var elem BasicStruct
-				t, err = d.ReadToken()
-				if err != nil {
-					return err
+			name := t.String()
+			if matchCaseInsensitiveNames {
+				switch foldNameNestedStruct(name) {
+				case "ID":
+					name = "id"
+				case "PROFILE":
+					name = "profile"
+				case "TAGS":
+					name = "tags"
 				}
-				if t.Kind() != '{' {
-					return errors.New("expected object start, got " + string(t.Kind()))
+			}
+			switch name {
+			case "id":
+				if seen&(1<<0) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+					return duplicateNameErrorNestedStruct(d, t)
 				}
-				for d.PeekKind() != '}' {
+				seen |= 1 << 0
+				if d.PeekKind() == 'n' {
+					if _, err = d.ReadToken(); err != nil {
+						return err
+					}
+					if !mergeWithLegacySemantics {
+						(*p).ID = 0
+					}
+				} else {
 					t, err = d.ReadToken()
 					if err != nil {
 						return err
 					}
-					if t.Kind() != '"' {
-						return errors.New("expected string, got " + string(t.Kind()))
-					}
-					switch t.String() {
-					case "name":
-						t, err = d.ReadToken()
-						if err != nil {
-							return err
-						} 
-						if t.Kind() != '"' {
-							return errors.New("expected string, got " + string(t.Kind()))
+					if t.Kind() != '0' || stringifyNumbers {
+						if !stringifyNumbers || t.Kind() != '"' {
+							return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), nil)
 						}
-						(elem).Name = string(t.String())
-					case "age":
-						t, err = d.ReadToken()
-						if err != nil {
-							return err
+						if !isNumberNestedStruct(t.String()) {
+							return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), strconv.ErrSyntax)
 						}
-						if t.Kind() != '0' {
-							return errors.New("expected number, got " + string(t.Kind()))
+					}
+					if n, err := strconv.ParseInt(t.String(), 10, 0); err != nil {
+						return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), err)
+					} else {
+						(*p).ID = int(n)
+					}
+				}
+			case "profile":
+				if seen&(1<<1) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+					return duplicateNameErrorNestedStruct(d, t)
+				}
+				seen |= 1 << 1
+				if d.PeekKind() == 'n' {
+					if _, err = d.ReadToken(); err != nil {
+						return err
+					}
+					(*p).Profile = nil
+				} else {
+					t, err = d.ReadToken()
+					if err != nil {
+						return err
+					}
+					if t.Kind() != '[' {
+						return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).Profile).Elem(), nil)
+					}
+					if (*p).Profile == nil {
+						(*p).Profile = []BasicStruct{}
+					} else {
+						(*p).Profile = (*p).Profile[:0]
+					}
+					for d.PeekKind() != ']' {
+						var elem BasicStruct
+						if mergeWithLegacySemantics && len((*p).Profile) < cap((*p).Profile) {
+							// Merge into the previous element
+							elem = (*p).Profile[:len((*p).Profile)+1][len((*p).Profile)]
 						}
-						(elem).Age = int(t.Int())
-					case "email":
-						t, err = d.ReadToken()
-						if err != nil {
-							return err
-						} 
-						if t.Kind() != '"' {
-							return errors.New("expected string, got " + string(t.Kind()))
+						if d.PeekKind() == 'n' {
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+							if !mergeWithLegacySemantics {
+								elem = BasicStruct{}
+							}
+						} else {
+							t, err = d.ReadToken()
+							if err != nil {
+								return err
+							}
+							if t.Kind() != '{' {
+								return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), nil)
+							}
+							var seen uint64
+							for d.PeekKind() != '}' {
+								t, err = d.ReadToken()
+								if err != nil {
+									return err
+								}
+								name := t.String()
+								if matchCaseInsensitiveNames {
+									switch foldNameNestedStruct(name) {
+									case "NAME":
+										name = "name"
+									case "AGE":
+										name = "age"
+									case "EMAIL":
+										name = "email"
+									case "ACTIVE":
+										name = "active"
+									}
+								}
+								switch name {
+								case "name":
+									if seen&(1<<0) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+										return duplicateNameErrorNestedStruct(d, t)
+									}
+									seen |= 1 << 0
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem.Name = ""
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != '"' {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Name).Elem(), nil)
+										}
+										elem.Name = string(t.String())
+									}
+								case "age":
+									if seen&(1<<1) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+										return duplicateNameErrorNestedStruct(d, t)
+									}
+									seen |= 1 << 1
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem.Age = 0
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != '0' || stringifyNumbers {
+											if !stringifyNumbers || t.Kind() != '"' {
+												return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), nil)
+											}
+											if !isNumberNestedStruct(t.String()) {
+												return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), strconv.ErrSyntax)
+											}
+										}
+										if n, err := strconv.ParseInt(t.String(), 10, 0); err != nil {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), err)
+										} else {
+											elem.Age = int(n)
+										}
+									}
+								case "email":
+									if seen&(1<<2) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+										return duplicateNameErrorNestedStruct(d, t)
+									}
+									seen |= 1 << 2
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem.Email = ""
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != '"' {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Email).Elem(), nil)
+										}
+										elem.Email = string(t.String())
+									}
+								case "active":
+									if seen&(1<<3) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+										return duplicateNameErrorNestedStruct(d, t)
+									}
+									seen |= 1 << 3
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem.Active = false
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != 't' && t.Kind() != 'f' {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Active).Elem(), nil)
+										}
+										elem.Active = t.Kind() == 't'
+									}
+								default:
+									if getOptionNestedStruct(d.Options(), json.RejectUnknownMembers) {
+										return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), json.ErrUnknownName)
+									}
+									if err = d.SkipValue(); err != nil {
+										return err
+									}
+								}
+							}
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
 						}
-						(elem).Email = string(t.String())
-					case "active":
-						t, err = d.ReadToken()
-						if err != nil {
-							return err
+						(*p).Profile = append((*p).Profile, elem)
+					}
+					if _, err = d.ReadToken(); err != nil {
+						return err
+					}
+				}
+			case "tags":
+				if seen&(1<<2) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+					return duplicateNameErrorNestedStruct(d, t)
+				}
+				seen |= 1 << 2
+				if d.PeekKind() == 'n' {
+					if _, err = d.ReadToken(); err != nil {
+						return err
+					}
+					(*p).Tags = nil
+				} else {
+					t, err = d.ReadToken()
+					if err != nil {
+						return err
+					}
+					if t.Kind() != '[' {
+						return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).Tags).Elem(), nil)
+					}
+					if (*p).Tags == nil {
+						(*p).Tags = []string{}
+					} else {
+						(*p).Tags = (*p).Tags[:0]
+					}
+					for d.PeekKind() != ']' {
+						var elem string
+						if mergeWithLegacySemantics && len((*p).Tags) < cap((*p).Tags) {
+							// Merge into the previous element
+							elem = (*p).Tags[:len((*p).Tags)+1][len((*p).Tags)]
 						}
-						if t.Kind() != 't' && t.Kind() != 'f' {
-							return errors.New("expected bool, got " + string(t.Kind()))
+						if d.PeekKind() == 'n' {
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+							if !mergeWithLegacySemantics {
+								elem = ""
+							}
+						} else {
+							t, err = d.ReadToken()
+							if err != nil {
+								return err
+							}
+							if t.Kind() != '"' {
+								return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), nil)
+							}
+							elem = string(t.String())
 						}
-						(elem).Active = t.Kind() == 't'
-					default:
-						d.SkipValue()
+						(*p).Tags = append((*p).Tags, elem)
+					}
+					if _, err = d.ReadToken(); err != nil {
+						return err
 					}
 				}
-				_, _ = d.ReadToken()
-				(*p).Profile = append((*p).Profile, elem)
+			default:
+				if getOptionNestedStruct(d.Options(), json.RejectUnknownMembers) {
+					return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p)).Elem(), json.ErrUnknownName)
+				}
+				if err = d.SkipValue(); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err = d.ReadToken(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSONCollect decodes b into p like UnmarshalJSON, but does
+// not stop at values that cannot be decoded into p: they are skipped
+// and decoding goes on. It returns all the errors joined with
+// errors.Join, each wrapping a *json.SemanticError locating an
+// invalid value, prefixed with its line and column.
+func (p *NestedStruct) UnmarshalJSONCollect(b []byte) error {
+	c := collectNestedStruct{p: p}
+	if err := json.Unmarshal(b, &c); err != nil {
+		c.errs = append(c.errs, err)
+	}
+	for i, err := range c.errs {
+		c.errs[i] = positionErrorNestedStruct(b, err)
+	}
+	return errors.Join(c.errs...)
+}
+
+// collectNestedStruct decodes into p, collecting semantic errors in errs.
+type collectNestedStruct struct {
+	p    *NestedStruct
+	errs []error
+}
+
+// value decodes the value of type goType starting at depth with
+// decode. On a semantic error, the error is collected and the rest
+// of the value skipped.
+func (c *collectNestedStruct) value(d *jsontext.Decoder, depth int, goType reflect.Type, decode func() error) error {
+	err := decode()
+	var serr *json.SemanticError
+	if !errors.As(err, &serr) {
+		return err
+	}
+	c.errs = append(c.errs, annotateErrorNestedStruct(d, goType, serr))
+	for d.StackDepth() > depth {
+		if _, err := d.ReadToken(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *collectNestedStruct) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+	p := c.p
+	var (
+		t   jsontext.Token
+		err error
+	)
+	opts := d.Options()
+	matchCaseInsensitiveNames, _ := json.GetOption(opts, json.MatchCaseInsensitiveNames)
+	stringifyNumbers, _ := json.GetOption(opts, json.StringifyNumbers)
+	mergeWithLegacySemantics, _ := json.GetOption(opts, jsonv1.MergeWithLegacySemantics)
+	if err = c.value(d, d.StackDepth(), reflect.TypeOf(&(*p)).Elem(), func() error {
+		if d.PeekKind() == 'n' {
+			if _, err = d.ReadToken(); err != nil {
+				return err
+			}
+			if !mergeWithLegacySemantics {
+				(*p) = NestedStruct{}
 			}
-			_, _ = d.ReadToken()
-		case "tags":
+		} else {
 			t, err = d.ReadToken()
 			if err != nil {
 				return err
 			}
-			if t.Kind() != '[' {
-				return errors.New("expected array start, got " + string(t.Kind()))
+			if t.Kind() != '{' {
+				return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p)).Elem(), nil)
 			}
-			(*p).Tags = nil
-			for d.PeekKind() != ']' {
-				var elem string
+			var seen uint64
+			for d.PeekKind() != '}' {
 				t, err = d.ReadToken()
 				if err != nil {
 					return err
-				} 
-				if t.Kind() != '"' {
-					return errors.New("expected string, got " + string(t.Kind()))
 				}
-				elem = string(t.String())
-				(*p).Tags = append((*p).Tags, elem)
+				name := t.String()
+				if matchCaseInsensitiveNames {
+					switch foldNameNestedStruct(name) {
+					case "ID":
+						name = "id"
+					case "PROFILE":
+						name = "profile"
+					case "TAGS":
+						name = "tags"
+					}
+				}
+				switch name {
+				case "id":
+					if seen&(1<<0) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+						return duplicateNameErrorNestedStruct(d, t)
+					}
+					seen |= 1 << 0
+					if err = c.value(d, d.StackDepth(), reflect.TypeOf(&(*p).ID).Elem(), func() error {
+						if d.PeekKind() == 'n' {
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+							if !mergeWithLegacySemantics {
+								(*p).ID = 0
+							}
+						} else {
+							t, err = d.ReadToken()
+							if err != nil {
+								return err
+							}
+							if t.Kind() != '0' || stringifyNumbers {
+								if !stringifyNumbers || t.Kind() != '"' {
+									return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), nil)
+								}
+								if !isNumberNestedStruct(t.String()) {
+									return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), strconv.ErrSyntax)
+								}
+							}
+							if n, err := strconv.ParseInt(t.String(), 10, 0); err != nil {
+								return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).ID).Elem(), err)
+							} else {
+								(*p).ID = int(n)
+							}
+						}
+						return nil
+					}); err != nil {
+						return err
+					}
+				case "profile":
+					if seen&(1<<1) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+						return duplicateNameErrorNestedStruct(d, t)
+					}
+					seen |= 1 << 1
+					if err = c.value(d, d.StackDepth(), reflect.TypeOf(&(*p).Profile).Elem(), func() error {
+						if d.PeekKind() == 'n' {
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+							(*p).Profile = nil
+						} else {
+							t, err = d.ReadToken()
+							if err != nil {
+								return err
+							}
+							if t.Kind() != '[' {
+								return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).Profile).Elem(), nil)
+							}
+							if (*p).Profile == nil {
+								(*p).Profile = []BasicStruct{}
+							} else {
+								(*p).Profile = (*p).Profile[:0]
+							}
+							for d.PeekKind() != ']' {
+								var elem BasicStruct
+								if mergeWithLegacySemantics && len((*p).Profile) < cap((*p).Profile) {
+									// Merge into the previous element
+									elem = (*p).Profile[:len((*p).Profile)+1][len((*p).Profile)]
+								}
+								if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem).Elem(), func() error {
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem = BasicStruct{}
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != '{' {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), nil)
+										}
+										var seen uint64
+										for d.PeekKind() != '}' {
+											t, err = d.ReadToken()
+											if err != nil {
+												return err
+											}
+											name := t.String()
+											if matchCaseInsensitiveNames {
+												switch foldNameNestedStruct(name) {
+												case "NAME":
+													name = "name"
+												case "AGE":
+													name = "age"
+												case "EMAIL":
+													name = "email"
+												case "ACTIVE":
+													name = "active"
+												}
+											}
+											switch name {
+											case "name":
+												if seen&(1<<0) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+													return duplicateNameErrorNestedStruct(d, t)
+												}
+												seen |= 1 << 0
+												if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem.Name).Elem(), func() error {
+													if d.PeekKind() == 'n' {
+														if _, err = d.ReadToken(); err != nil {
+															return err
+														}
+														if !mergeWithLegacySemantics {
+															elem.Name = ""
+														}
+													} else {
+														t, err = d.ReadToken()
+														if err != nil {
+															return err
+														}
+														if t.Kind() != '"' {
+															return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Name).Elem(), nil)
+														}
+														elem.Name = string(t.String())
+													}
+													return nil
+												}); err != nil {
+													return err
+												}
+											case "age":
+												if seen&(1<<1) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+													return duplicateNameErrorNestedStruct(d, t)
+												}
+												seen |= 1 << 1
+												if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem.Age).Elem(), func() error {
+													if d.PeekKind() == 'n' {
+														if _, err = d.ReadToken(); err != nil {
+															return err
+														}
+														if !mergeWithLegacySemantics {
+															elem.Age = 0
+														}
+													} else {
+														t, err = d.ReadToken()
+														if err != nil {
+															return err
+														}
+														if t.Kind() != '0' || stringifyNumbers {
+															if !stringifyNumbers || t.Kind() != '"' {
+																return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), nil)
+															}
+															if !isNumberNestedStruct(t.String()) {
+																return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), strconv.ErrSyntax)
+															}
+														}
+														if n, err := strconv.ParseInt(t.String(), 10, 0); err != nil {
+															return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Age).Elem(), err)
+														} else {
+															elem.Age = int(n)
+														}
+													}
+													return nil
+												}); err != nil {
+													return err
+												}
+											case "email":
+												if seen&(1<<2) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+													return duplicateNameErrorNestedStruct(d, t)
+												}
+												seen |= 1 << 2
+												if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem.Email).Elem(), func() error {
+													if d.PeekKind() == 'n' {
+														if _, err = d.ReadToken(); err != nil {
+															return err
+														}
+														if !mergeWithLegacySemantics {
+															elem.Email = ""
+														}
+													} else {
+														t, err = d.ReadToken()
+														if err != nil {
+															return err
+														}
+														if t.Kind() != '"' {
+															return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Email).Elem(), nil)
+														}
+														elem.Email = string(t.String())
+													}
+													return nil
+												}); err != nil {
+													return err
+												}
+											case "active":
+												if seen&(1<<3) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+													return duplicateNameErrorNestedStruct(d, t)
+												}
+												seen |= 1 << 3
+												if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem.Active).Elem(), func() error {
+													if d.PeekKind() == 'n' {
+														if _, err = d.ReadToken(); err != nil {
+															return err
+														}
+														if !mergeWithLegacySemantics {
+															elem.Active = false
+														}
+													} else {
+														t, err = d.ReadToken()
+														if err != nil {
+															return err
+														}
+														if t.Kind() != 't' && t.Kind() != 'f' {
+															return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem.Active).Elem(), nil)
+														}
+														elem.Active = t.Kind() == 't'
+													}
+													return nil
+												}); err != nil {
+													return err
+												}
+											default:
+												if getOptionNestedStruct(d.Options(), json.RejectUnknownMembers) {
+													return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), json.ErrUnknownName)
+												}
+												if err = d.SkipValue(); err != nil {
+													return err
+												}
+											}
+										}
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+									}
+									return nil
+								}); err != nil {
+									return err
+								}
+								(*p).Profile = append((*p).Profile, elem)
+							}
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+						}
+						return nil
+					}); err != nil {
+						return err
+					}
+				case "tags":
+					if seen&(1<<2) != 0 && !getOptionNestedStruct(d.Options(), jsontext.AllowDuplicateNames) {
+						return duplicateNameErrorNestedStruct(d, t)
+					}
+					seen |= 1 << 2
+					if err = c.value(d, d.StackDepth(), reflect.TypeOf(&(*p).Tags).Elem(), func() error {
+						if d.PeekKind() == 'n' {
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+							(*p).Tags = nil
+						} else {
+							t, err = d.ReadToken()
+							if err != nil {
+								return err
+							}
+							if t.Kind() != '[' {
+								return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p).Tags).Elem(), nil)
+							}
+							if (*p).Tags == nil {
+								(*p).Tags = []string{}
+							} else {
+								(*p).Tags = (*p).Tags[:0]
+							}
+							for d.PeekKind() != ']' {
+								var elem string
+								if mergeWithLegacySemantics && len((*p).Tags) < cap((*p).Tags) {
+									// Merge into the previous element
+									elem = (*p).Tags[:len((*p).Tags)+1][len((*p).Tags)]
+								}
+								if err = c.value(d, d.StackDepth(), reflect.TypeOf(&elem).Elem(), func() error {
+									if d.PeekKind() == 'n' {
+										if _, err = d.ReadToken(); err != nil {
+											return err
+										}
+										if !mergeWithLegacySemantics {
+											elem = ""
+										}
+									} else {
+										t, err = d.ReadToken()
+										if err != nil {
+											return err
+										}
+										if t.Kind() != '"' {
+											return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&elem).Elem(), nil)
+										}
+										elem = string(t.String())
+									}
+									return nil
+								}); err != nil {
+									return err
+								}
+								(*p).Tags = append((*p).Tags, elem)
+							}
+							if _, err = d.ReadToken(); err != nil {
+								return err
+							}
+						}
+						return nil
+					}); err != nil {
+						return err
+					}
+				default:
+					if getOptionNestedStruct(d.Options(), json.RejectUnknownMembers) {
+						return unmarshalErrorNestedStruct(d, t, reflect.TypeOf(&(*p)).Elem(), json.ErrUnknownName)
+					}
+					if err = d.SkipValue(); err != nil {
+						return err
+					}
+				}
+			}
+			if _, err = d.ReadToken(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *NestedStruct) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *NestedStruct) MarshalJSONTo(e *jsontext.Encoder) error {
+	opts := e.Options()
+	stringifyNumbers, _ := json.GetOption(opts, json.StringifyNumbers)
+	var err error
+	if err = e.WriteToken(jsontext.BeginObject); err != nil {
+		return err
+	}
+	if !((*p).ID == 0 && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+		if err = e.WriteToken(jsontext.String("id")); err != nil {
+			return err
+		}
+		if !stringifyNumbers {
+			err = e.WriteToken(jsontext.Int(int64((*p).ID)))
+		} else {
+			err = e.WriteToken(jsontext.String(jsontext.Int(int64((*p).ID)).String()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !((*p).Profile == nil && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+		if err = e.WriteToken(jsontext.String("profile")); err != nil {
+			return err
+		}
+		if (*p).Profile == nil && getOptionNestedStruct(e.Options(), json.FormatNilSliceAsNull) {
+			if err = e.WriteToken(jsontext.Null); err != nil {
+				return err
+			}
+		} else {
+			if err = e.WriteToken(jsontext.BeginArray); err != nil {
+				return err
+			}
+			for _, elem := range (*p).Profile {
+				if err = e.WriteToken(jsontext.BeginObject); err != nil {
+					return err
+				}
+				if !(elem.Name == "" && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+					if err = e.WriteToken(jsontext.String("name")); err != nil {
+						return err
+					}
+					if err = e.WriteToken(jsontext.String(string(elem.Name))); err != nil {
+						return err
+					}
+				}
+				if !(elem.Age == 0 && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+					if err = e.WriteToken(jsontext.String("age")); err != nil {
+						return err
+					}
+					if !stringifyNumbers {
+						err = e.WriteToken(jsontext.Int(int64(elem.Age)))
+					} else {
+						err = e.WriteToken(jsontext.String(jsontext.Int(int64(elem.Age)).String()))
+					}
+					if err != nil {
+						return err
+					}
+				}
+				if !(elem.Email == "" && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+					if err = e.WriteToken(jsontext.String("email")); err != nil {
+						return err
+					}
+					if err = e.WriteToken(jsontext.String(string(elem.Email))); err != nil {
+						return err
+					}
+				}
+				if !(!elem.Active && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+					if err = e.WriteToken(jsontext.String("active")); err != nil {
+						return err
+					}
+					if err = e.WriteToken(jsontext.Bool(bool(elem.Active))); err != nil {
+						return err
+					}
+				}
+				if err = e.WriteToken(jsontext.EndObject); err != nil {
+					return err
+				}
+			}
+			if err = e.WriteToken(jsontext.EndArray); err != nil {
+				return err
 			}
-			_, _ = d.ReadToken()
-		default:
-			d.SkipValue()
 		}
 	}
-	_, _ = d.ReadToken()
+	if !((*p).Tags == nil && getOptionNestedStruct(e.Options(), json.OmitZeroStructFields)) {
+		if err = e.WriteToken(jsontext.String("tags")); err != nil {
+			return err
+		}
+		if (*p).Tags == nil && getOptionNestedStruct(e.Options(), json.FormatNilSliceAsNull) {
+			if err = e.WriteToken(jsontext.Null); err != nil {
+				return err
+			}
+		} else {
+			if err = e.WriteToken(jsontext.BeginArray); err != nil {
+				return err
+			}
+			for _, elem := range (*p).Tags {
+				if err = e.WriteToken(jsontext.String(string(elem))); err != nil {
+					return err
+				}
+			}
+			if err = e.WriteToken(jsontext.EndArray); err != nil {
+				return err
+			}
+		}
+	}
+	if err = e.WriteToken(jsontext.EndObject); err != nil {
+		return err
+	}
 	return nil
 }
+
+// annotateErrorNestedStructUnmarshaler returns err from UnmarshalJSONFrom.
+type annotateErrorNestedStructUnmarshaler struct{ err error }
+
+func (u *annotateErrorNestedStructUnmarshaler) UnmarshalJSONFrom(*jsontext.Decoder) error {
+	return u.err
+}
+
+// annotateErrorNestedStruct returns err, which occurred decoding a value of type
+// goType from d, located where d stands if err lacks a location
+// and d is not nil.
+func annotateErrorNestedStruct(d *jsontext.Decoder, goType reflect.Type, err *json.SemanticError) error {
+	serr := &json.SemanticError{
+		ByteOffset:  err.ByteOffset,
+		JSONPointer: err.JSONPointer,
+		JSONKind:    err.JSONKind,
+		JSONValue:   err.JSONValue,
+		GoType:      cmp.Or(err.GoType, goType),
+		Err:         err.Err,
+	}
+	if d != nil {
+		serr.ByteOffset = cmp.Or(serr.ByteOffset, d.InputOffset())
+		serr.JSONPointer = cmp.Or(serr.JSONPointer, d.StackPointer())
+	}
+	// Only json/v2 records that the error occurred when
+	// unmarshaling, which its message states: serr is complete,
+	// so that nothing else is taken from this call
+	json.Unmarshal([]byte("null"), &annotateErrorNestedStructUnmarshaler{serr})
+	return serr
+}
+
+// duplicateNameErrorNestedStruct returns the error for the member name t just read from d
+// that duplicates a previous member of the object.
+func duplicateNameErrorNestedStruct(d *jsontext.Decoder, t jsontext.Token) error {
+	b, _ := jsontext.AppendQuote(nil, t.String())
+	return &jsontext.SyntacticError{
+		ByteOffset:  d.InputOffset() - int64(len(b)),
+		JSONPointer: d.StackPointer(),
+		Err:         jsontext.ErrDuplicateName,
+	}
+}
+
+// foldNameNestedStruct folds name the way JSON member names are matched
+// case-insensitively, ignoring '_' and '-'.
+func foldNameNestedStruct(name string) string {
+	b := make([]byte, 0, len(name))
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			if r == '_' || r == '-' {
+				continue
+			}
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b = append(b, byte(r))
+			continue
+		}
+		for {
+			r2 := unicode.SimpleFold(r)
+			if r2 <= r {
+				r = r2
+				break
+			}
+			r = r2
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b)
+}
+
+// getOptionNestedStruct reports whether the boolean option of setter is set in
+// opts.
+func getOptionNestedStruct(opts json.Options, setter func(bool) json.Options) bool {
+	v, _ := json.GetOption(opts, setter)
+	return v
+}
+
+// isNumberNestedStruct reports whether s is a valid JSON number.
+func isNumberNestedStruct(s string) bool {
+	return strings.TrimSpace(s) == s && jsontext.Value(s).Kind() == '0' && jsontext.Value(s).IsValid()
+}
+
+// positionErrorNestedStruct prefixes err, returned when decoding b, with the line and
+// column of the byte it occurred at, both starting at 1. Columns
+// count bytes.
+func positionErrorNestedStruct(b []byte, err error) error {
+	var offset int64
+	var serr *json.SemanticError
+	var synErr *jsontext.SyntacticError
+	switch {
+	case errors.As(err, &synErr):
+		offset = synErr.ByteOffset
+	case errors.As(err, &serr):
+		offset = serr.ByteOffset
+	default:
+		return err
+	}
+	if offset < 0 || offset > int64(len(b)) {
+		return err
+	}
+	line := 1 + bytes.Count(b[:offset], []byte("\n"))
+	column := int(offset) - bytes.LastIndexByte(b[:offset], '\n')
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
+// unmarshalErrorNestedStruct returns the error for the token t just read from d that
+// could not be decoded into a value of type goType.
+func unmarshalErrorNestedStruct(d *jsontext.Decoder, t jsontext.Token, goType reflect.Type, err error) error {
+	var n int
+	switch t.Kind() {
+	case 'n':
+		n = len("null")
+	case 't':
+		n = len("true")
+	case 'f':
+		n = len("false")
+	case '"':
+		// Exact unless the input escaped more than necessary
+		b, _ := jsontext.AppendQuote(nil, t.String())
+		n = len(b)
+	case '0':
+		n = len(t.String())
+	default:
+		n = len("{")
+	}
+	serr := &json.SemanticError{
+		ByteOffset:  d.InputOffset() - int64(n),
+		JSONPointer: d.StackPointer(),
+		JSONKind:    t.Kind(),
+		GoType:      goType,
+		Err:         err,
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+		serr.Err = err
+	}
+	if err == strconv.ErrSyntax || err == strconv.ErrRange {
+		// Invalid or out of range numbers report the raw value
+		if t.Kind() == '"' {
+			serr.JSONValue, _ = jsontext.AppendQuote(nil, t.String())
+		} else {
+			serr.JSONValue = jsontext.Value(t.String())
+		}
+	}
+	return serr
+}
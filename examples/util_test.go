@@ -1,6 +1,7 @@
 package examples_test
 
 import (
+	"encoding/json/v2"
 	"fmt"
 	"reflect"
 	"slices"
@@ -177,3 +178,22 @@ func sdiff(a, b any) []string {
 	walk("", reflect.ValueOf(a), reflect.ValueOf(b))
 	return diffs
 }
+
+// equalJSON reports whether a and b hold the same JSON value, ignoring
+// whitespace and the order of object members.
+func equalJSON(a, b []byte) bool {
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// errorText returns the message of err, with the modal verb json/v2 picks at
+// random once per process, "cannot" or "unable to", normalized to "cannot".
+func errorText(err error) string {
+	return strings.ReplaceAll(fmt.Sprint(err), "json: unable to ", "json: cannot ")
+}
@@ -0,0 +1,156 @@
+package gen
+
+import "fmt"
+
+// useUnmarshalAny emits a helper decoding a JSON value straight from the token
+// stream into the Go value json.Unmarshal would store in an any: nil, bool,
+// string, float64 (or json.Number with -usenumber), map[string]any or []any.
+// With limits, the helper takes the limits to enforce and the depth MaxDepth
+// is counted from. It returns the name of the helper.
+func (g *generator) useUnmarshalAny() string {
+	return g.useHelper("unmarshalAny", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors")
+		number := "strconv.ParseFloat(t.String(), 64)"
+		if g.cfg.UseNumber {
+			h.useImports("encoding/json")
+			number = h.imports.use("encoding/json") + ".Number(t.String()), nil"
+		} else {
+			h.useImports("strconv")
+		}
+		param, arg := "", g.limitsArg()
+		var depth, members, elements, key, merge string
+		value := `
+					return t.String(), nil`
+		if g.cfg.limited() {
+			h.useImports("reflect")
+			param = ", limits *JSONLimits, baseDepth int"
+			depth, members, elements, key, value = limitChecks(h.useLimitError())
+			// json/v2 decodes into existing values, checking the same limits
+			merge = ", " + h.useLimitUnmarshalers() + "(d, limits, baseDepth)"
+		}
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s decodes the next JSON value as json.Unmarshal would
+			// decode it into the any v.
+			func %[1]s(d *jsontext.Decoder, v any%[3]s) (any, error) {
+				if v != nil && d.PeekKind() != 'n' {
+					// Decoding into an existing value depends on its type
+					err := json.UnmarshalDecode(d, &v%[10]s)
+					return v, err
+				}
+				t, err := d.ReadToken()
+				if err != nil {
+					return nil, err
+				}
+				switch t.Kind() {
+				case 'n':
+					return nil, nil
+				case 't', 'f':
+					return t.Bool(), nil
+				case '"':%[9]s
+				case '0':
+					return %[2]s
+				case '{':%[5]s
+					m := make(map[string]any)
+					for d.PeekKind() != '}' {%[6]s
+						t, err = d.ReadToken()
+						if err != nil {
+							return nil, err
+						}
+						key := t.String()%[8]s
+						if m[key], err = %[1]s(d, nil%[4]s); err != nil {
+							return nil, err
+						}
+					}
+					if _, err = d.ReadToken(); err != nil {
+						return nil, err
+					}
+					return m, nil
+				case '[':%[5]s
+					s := []any{}
+					for d.PeekKind() != ']' {%[7]s
+						v, err := %[1]s(d, nil%[4]s)
+						if err != nil {
+							return nil, err
+						}
+						s = append(s, v)
+					}
+					if _, err = d.ReadToken(); err != nil {
+						return nil, err
+					}
+					return s, nil
+				default:
+					return nil, errors.New("unexpected token " + string(t.Kind()))
+				}
+			}
+		`, name, number, param, arg, depth, members, elements, key, value, merge))
+	})
+}
+
+// useMarshalAny emits a helper encoding the dynamic values produced by
+// useUnmarshalAny without reflection, falling back to json.MarshalEncode for
+// any other value. It returns the name of the helper.
+func (g *generator) useMarshalAny() string {
+	return g.useHelper("marshalAny", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "maps", "math", "slices", "strconv")
+		nanError := h.marshalError("v", `errors.New("unsupported value: " + strconv.FormatFloat(v, 'g', -1, 64))`)
+		getOption := h.useGetOption()
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s encodes v as json.Marshal would, with maps in sorted
+			// key order if sorted is set, or with json.Deterministic.
+			func %[1]s(e *jsontext.Encoder, v any, sorted bool) error {
+				switch v := v.(type) {
+				case nil:
+					return e.WriteToken(jsontext.Null)
+				case bool:
+					return e.WriteToken(jsontext.Bool(v))
+				case string:
+					return e.WriteToken(jsontext.String(v))
+				case float64:
+					if math.IsNaN(v) || math.IsInf(v, 0) {
+						return %[2]s
+					}
+					return e.WriteToken(jsontext.Float(v))
+				case map[string]any:
+					if err := e.WriteToken(jsontext.BeginObject); err != nil {
+						return err
+					}
+					if len(v) > 1 && (sorted || %[3]s(e.Options(), json.Deterministic)) {
+						for _, key := range slices.Sorted(maps.Keys(v)) {
+							if err := e.WriteToken(jsontext.String(key)); err != nil {
+								return err
+							}
+							if err := %[1]s(e, v[key], sorted); err != nil {
+								return err
+							}
+						}
+						return e.WriteToken(jsontext.EndObject)
+					}
+					for key, value := range v {
+						if err := e.WriteToken(jsontext.String(key)); err != nil {
+							return err
+						}
+						if err := %[1]s(e, value, sorted); err != nil {
+							return err
+						}
+					}
+					return e.WriteToken(jsontext.EndObject)
+				case []any:
+					if err := e.WriteToken(jsontext.BeginArray); err != nil {
+						return err
+					}
+					for _, elem := range v {
+						if err := %[1]s(e, elem, sorted); err != nil {
+							return err
+						}
+					}
+					return e.WriteToken(jsontext.EndArray)
+				default:
+					if sorted {
+						return json.MarshalEncode(e, v, json.Deterministic(true))
+					}
+					return json.MarshalEncode(e, v)
+				}
+			}
+		`, name, nanError, getOption))
+	})
+}
@@ -0,0 +1,131 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Difference is a generated file that does not match the file on disk.
+type Difference struct {
+	Path   string // path of the file
+	Reason string // "stale", "missing" or "orphaned"
+	Diff   string // unified diff turning the file on disk into the generated one
+}
+
+// Check generates the files of the types selected by cfg like Generate, and
+// compares them with the files on disk. It returns the generated files that
+// are stale or missing, and the files generated by go-gen-json in the
+// packages for types no longer declared, which are orphaned. The files on
+// disk are up to date if there are none. Warnings are returned as Diagnostics
+// along with the differences.
+func Check(ctx context.Context, cfg Config) ([]Difference, error) {
+	files, pkgs, warnings := generate(ctx, cfg)
+	if files == nil {
+		return nil, warnings
+	}
+	var diffs []Difference
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		old, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			diffs = append(diffs, Difference{path, "missing", unifiedDiff("/dev/null", "", cfg.label(path), string(files[path]))})
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(old, files[path]):
+			diffs = append(diffs, Difference{path, "stale", unifiedDiff(cfg.label(path), string(old), cfg.label(path), string(files[path]))})
+		}
+	}
+	for _, p := range pkgs {
+		orphans, err := orphans(p, files)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range orphans {
+			old, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, Difference{path, "orphaned", unifiedDiff(cfg.label(path), string(old), "/dev/null", "")})
+		}
+	}
+	return diffs, warnings
+}
+
+// label returns the name of the file path in diffs, relative to the
+// directory of cfg when the file is in it.
+func (cfg *Config) label(path string) string {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return path
+	}
+	return rel
+}
+
+// orphans returns the paths of the files generated by go-gen-json in the
+// directory of package p, other than files, that declare methods of types no
+// longer declared in the package. Files are recognized by their header,
+// whatever they are named.
+func orphans(p *Package, files map[string][]byte) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(p.Dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var orphans []string
+	for _, path := range paths {
+		if _, ok := files[path]; ok {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, path, nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || !generatedByUs(f) {
+			// Files that do not parse are reported by the build
+			continue
+		}
+		if f, err = parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution); err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+				if _, ok := p.Types[receiverName(fn.Recv.List[0].Type)]; !ok {
+					orphans = append(orphans, path)
+					break
+				}
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// generatedByUs reports whether f was generated by go-gen-json.
+func generatedByUs(f *ast.File) bool {
+	return ast.IsGenerated(f) && len(f.Comments) > 0 && strings.Contains(f.Comments[0].Text(), "by go-gen-json.")
+}
+
+// receiverName returns the name of the type of a method receiver.
+func receiverName(expr ast.Expr) string {
+	switch ts := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(ts.X)
+	case *ast.ParenExpr:
+		return receiverName(ts.X)
+	case *ast.IndexExpr:
+		return receiverName(ts.X)
+	case *ast.Ident:
+		return ts.Name
+	}
+	return ""
+}
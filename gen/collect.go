@@ -0,0 +1,149 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// GenerateUnmarshalJSONCollect generates UnmarshalJSONCollect, which decodes
+// like UnmarshalJSON but keeps going after semantic errors. The decoding code
+// is generated again, with every member, element and map value decoded in a
+// closure, so that a semantic error only abandons the value it occurred in.
+func (g *generator) GenerateUnmarshalJSONCollect(typeName string, typeExpr ast.Expr) {
+	g.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect")
+	collector := "collect" + typeName
+	// Limits are fatal: decoding does not go on past an exceeded limit
+	size, fatal := "", ""
+	if g.cfg.limited() {
+		g.useImports("reflect")
+		size = fmt.Sprintf(`
+			if %[1]s.MaxBytes > 0 && len(b) > %[1]s.MaxBytes {
+				return %[2]s(nil, reflect.TypeOf(p).Elem(), "more than %%d bytes of input", %[1]s.MaxBytes)
+			}`, g.limitsVar(), g.useLimitError())
+		fatal = " || errors.Is(err, " + g.useErrLimit() + ")"
+	}
+	g.writeMultiline(fmt.Sprintf(`
+
+		// UnmarshalJSONCollect decodes b into p like UnmarshalJSON, but does
+		// not stop at values that cannot be decoded into p: they are skipped
+		// and decoding goes on. It returns all the errors joined with
+		// errors.Join, each wrapping a *json.SemanticError locating an
+		// invalid value, prefixed with its line and column.
+		func (p *%[1]s) UnmarshalJSONCollect(b []byte) error {%[4]s
+			c := %[2]s{p: p}
+			if err := json.Unmarshal(b, &c); err != nil {
+				c.errs = append(c.errs, err)
+			}
+			for i, err := range c.errs {
+				c.errs[i] = %[6]s(b, err)
+			}
+			return errors.Join(c.errs...)
+		}
+
+		// %[2]s decodes into p, collecting semantic errors in errs.
+		type %[2]s struct {
+			p    *%[1]s
+			errs []error
+		}
+
+		// value decodes the value of type goType starting at depth with
+		// decode. On a semantic error, the error is collected and the rest
+		// of the value skipped.
+		func (c *%[2]s) value(d *jsontext.Decoder, depth int, goType reflect.Type, decode func() error) error {
+			err := decode()
+			var serr *json.SemanticError
+			if !errors.As(err, &serr)%[5]s {
+				return err
+			}
+			c.errs = append(c.errs, %[3]s(d, goType, serr))
+			for d.StackDepth() > depth {
+				if _, err := d.ReadToken(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		func (c *%[2]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+			p := c.p
+			var (
+				t   jsontext.Token
+				err error
+			)
+	`, typeName, collector, g.useAnnotateError(), size, fatal, g.usePositionError()))
+	g.indent()
+	g.unmarshalerLimits()
+	g.collect = true
+	code := g.capture(func() {
+		g.unmarshalerValue(ast.NewIdent(typeName), "(*p)", func() { g.unmarshaler(typeName, typeExpr, "(*p)", typeName) })
+	})
+	g.collect = false
+	g.declareOptions("d")
+	g.unmarshalerBaseDepth(code)
+	g.body.WriteString(code)
+	g.writeLine("return nil")
+	g.unindent()
+	g.writeLine("}")
+}
+
+// unmarshalerValue emits the code decoding a member, element or top-level
+// value varExpr of type typeExpr: JSON null, then anything else with decode.
+// When collecting errors, the code runs in a closure passed to the value
+// method of the collector.
+func (g *generator) unmarshalerValue(typeExpr ast.Expr, varExpr string, decode func()) {
+	if !g.collect {
+		g.unmarshalerNull(typeExpr, varExpr, decode)
+		return
+	}
+	g.useImports("reflect")
+	g.writeLine(fmt.Sprintf("if err = c.value(d, d.StackDepth(), reflect.TypeOf(&%s).Elem(), func() error {", varExpr))
+	g.indent()
+	g.unmarshalerNull(typeExpr, varExpr, decode)
+	g.writeLine("return nil")
+	g.unindent()
+	g.writeMultiline(`
+		}); err != nil {
+			return err
+		}
+	`)
+}
+
+// useAnnotateError emits a helper building a collected error like
+// json.Unmarshal builds the error it returns. It returns the name of the
+// helper.
+func (g *generator) useAnnotateError() string {
+	return g.useHelper("annotateError", func(h *generator, name string) {
+		h.useImports("cmp", "encoding/json/jsontext", "encoding/json/v2", "reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]sUnmarshaler returns err from UnmarshalJSONFrom.
+			type %[1]sUnmarshaler struct{ err error }
+
+			func (u *%[1]sUnmarshaler) UnmarshalJSONFrom(*jsontext.Decoder) error {
+				return u.err
+			}
+
+			// %[1]s returns err, which occurred decoding a value of type
+			// goType from d, located where d stands if err lacks a location
+			// and d is not nil.
+			func %[1]s(d *jsontext.Decoder, goType reflect.Type, err *json.SemanticError) error {
+				serr := &json.SemanticError{
+					ByteOffset:  err.ByteOffset,
+					JSONPointer: err.JSONPointer,
+					JSONKind:    err.JSONKind,
+					JSONValue:   err.JSONValue,
+					GoType:      cmp.Or(err.GoType, goType),
+					Err:         err.Err,
+				}
+				if d != nil {
+					serr.ByteOffset = cmp.Or(serr.ByteOffset, d.InputOffset())
+					serr.JSONPointer = cmp.Or(serr.JSONPointer, d.StackPointer())
+				}
+				// Only json/v2 records that the error occurred when
+				// unmarshaling, which its message states: serr is complete,
+				// so that nothing else is taken from this call
+				json.Unmarshal([]byte("null"), &%[1]sUnmarshaler{serr})
+				return serr
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,346 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configFileNames are the names of the configuration files looked up in the
+// directory of each package and its parents.
+var configFileNames = []string{"gen-json.toml", "gen-json.json"}
+
+// settings are the settings of a configuration file, nil when unset. Their
+// names are the names of the flags in kebab case.
+type settings struct {
+	Strict           *bool             `json:"strict"`
+	RejectUnknown    *bool             `json:"reject-unknown"`
+	RejectDuplicates *bool             `json:"reject-duplicates"`
+	IgnoreCase       *bool             `json:"ignore-case"`
+	UseNumber        *bool             `json:"use-number"`
+	Collect          *bool             `json:"collect"`
+	Deterministic    *bool             `json:"deterministic"`
+	MarshalOnly      *bool             `json:"marshal-only"`
+	Limits           *bool             `json:"limits"`
+	Fingerprint      *bool             `json:"fingerprint"`
+	Fallback         *bool             `json:"fallback"`
+	MaxDepth         *int              `json:"max-depth"`
+	MaxBytes         *int              `json:"max-bytes"`
+	MaxElements      *int              `json:"max-elements"`
+	MaxMembers       *int              `json:"max-members"`
+	MaxStringLength  *int              `json:"max-string"`
+	NilSlice         *string           `json:"nil-slice"`
+	TimeFormat       *string           `json:"time-format"`
+	Output           *string           `json:"output"`
+	Tags             []string          `json:"tags"`
+	Codecs           map[string]string `json:"codecs"`
+}
+
+// apply sets the options set by s in o.
+func (s *settings) apply(o *Options) {
+	set := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	if s.Strict != nil {
+		o.RejectUnknown, o.RejectDuplicates = *s.Strict, *s.Strict
+	}
+	set(&o.RejectUnknown, s.RejectUnknown)
+	set(&o.RejectDuplicates, s.RejectDuplicates)
+	set(&o.IgnoreCase, s.IgnoreCase)
+	set(&o.UseNumber, s.UseNumber)
+	set(&o.Collect, s.Collect)
+	set(&o.Deterministic, s.Deterministic)
+	set(&o.MarshalOnly, s.MarshalOnly)
+	set(&o.Limits, s.Limits)
+	set(&o.Fingerprint, s.Fingerprint)
+	set(&o.Fallback, s.Fallback)
+	for _, limit := range []struct {
+		dst *int
+		src *int
+	}{
+		{&o.MaxDepth, s.MaxDepth},
+		{&o.MaxBytes, s.MaxBytes},
+		{&o.MaxElements, s.MaxElements},
+		{&o.MaxMembers, s.MaxMembers},
+		{&o.MaxStringLength, s.MaxStringLength},
+	} {
+		if limit.src != nil {
+			*limit.dst = *limit.src
+		}
+	}
+	if s.NilSlice != nil {
+		o.NilSlice = *s.NilSlice
+	}
+	if s.TimeFormat != nil {
+		o.TimeFormat = *s.TimeFormat
+	}
+	if len(s.Codecs) > 0 {
+		// Do not modify the codecs of the Options the file applies to
+		codecs := maps.Clone(o.Codecs)
+		if codecs == nil {
+			codecs = make(map[string]string)
+		}
+		maps.Copy(codecs, s.Codecs)
+		o.Codecs = codecs
+	}
+}
+
+// findConfigFile returns the path of the configuration file nearest to the
+// directory dir: in dir, or else in the closest of its parents, up to the
+// root of the module containing dir. It returns "" if there is none, and an
+// error if a directory has both a TOML and a JSON file.
+func findConfigFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		var found []string
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				found = append(found, path)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+		switch {
+		case len(found) > 1:
+			return "", fmt.Errorf("%s: conflicting configuration files %s", dir, strings.Join(configFileNames, " and "))
+		case len(found) == 1:
+			return found[0], nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// readConfigFile reads the settings of the configuration file path, and
+// returns a problem if it is invalid.
+func readConfigFile(path string) (*settings, *Diagnostic) {
+	problem := func(line int, column int, format string, args ...any) *Diagnostic {
+		return &Diagnostic{Pos: token.Position{Filename: path, Line: line, Column: column}, Message: fmt.Sprintf(format, args...)}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, problem(0, 0, "%v", err)
+	}
+	if filepath.Ext(path) == ".toml" {
+		table, line, err := parseTOML(data)
+		if err != nil {
+			return nil, problem(line, 0, "%v", err)
+		}
+		// Decode the table like the contents of a JSON file
+		if data, err = json.Marshal(table); err != nil {
+			return nil, problem(0, 0, "%v", err)
+		}
+	}
+	var s settings
+	if err := json.Unmarshal(data, &s, json.RejectUnknownMembers(true)); err != nil {
+		var offset int64 = -1
+		var serr *json.SemanticError
+		var synErr *jsontext.SyntacticError
+		switch {
+		case filepath.Ext(path) == ".toml":
+		case errors.As(err, &serr):
+			offset = serr.ByteOffset
+		case errors.As(err, &synErr):
+			offset = synErr.ByteOffset
+		}
+		if offset < 0 {
+			return nil, problem(0, 0, "%v", err)
+		}
+		prefix := data[:min(int(offset), len(data))]
+		line := bytes.Count(prefix, []byte("\n")) + 1
+		return nil, problem(line, len(prefix)-bytes.LastIndexByte(prefix, '\n'), "%v", err)
+	}
+	return &s, nil
+}
+
+// parseTOML parses the subset of TOML configuration files are written in:
+// key/value pairs of strings, integers, booleans and single-line arrays of
+// them, in the root table or in tables of one level such as [codecs]. It
+// returns the line of the error if there is one.
+func parseTOML(data []byte) (map[string]any, int, error) {
+	root := make(map[string]any)
+	table := root
+	for i, line := range strings.Split(string(data), "\n") {
+		p := &tomlParser{s: line}
+		p.space()
+		switch {
+		case p.done():
+			continue
+		case p.s[0] == '[':
+			p.s = p.s[1:]
+			p.space()
+			name, err := p.key()
+			if err != nil {
+				return nil, i + 1, err
+			}
+			p.space()
+			if !strings.HasPrefix(p.s, "]") {
+				return nil, i + 1, errors.New("expected ] after table name")
+			}
+			p.s = p.s[1:]
+			p.space()
+			if !p.done() {
+				return nil, i + 1, fmt.Errorf("unexpected %q after table name", p.s)
+			}
+			if _, ok := root[name]; ok {
+				return nil, i + 1, fmt.Errorf("duplicate key %q", name)
+			}
+			table = make(map[string]any)
+			root[name] = table
+			continue
+		}
+		key, err := p.key()
+		if err != nil {
+			return nil, i + 1, err
+		}
+		p.space()
+		if !strings.HasPrefix(p.s, "=") {
+			return nil, i + 1, fmt.Errorf("expected = after key %q", key)
+		}
+		p.s = p.s[1:]
+		p.space()
+		value, err := p.value()
+		if err != nil {
+			return nil, i + 1, err
+		}
+		p.space()
+		if !p.done() {
+			return nil, i + 1, fmt.Errorf("unexpected %q after value of %q", p.s, key)
+		}
+		if _, ok := table[key]; ok {
+			return nil, i + 1, fmt.Errorf("duplicate key %q", key)
+		}
+		table[key] = value
+	}
+	return root, 0, nil
+}
+
+// tomlParser parses the rest s of a line of a TOML file.
+type tomlParser struct {
+	s string
+}
+
+// space skips spaces and tabs.
+func (p *tomlParser) space() {
+	p.s = strings.TrimLeft(p.s, " \t\r")
+}
+
+// done reports whether the rest of the line is empty or a comment.
+func (p *tomlParser) done() bool {
+	return p.s == "" || p.s[0] == '#'
+}
+
+// key parses a bare or quoted key.
+func (p *tomlParser) key() (string, error) {
+	if strings.HasPrefix(p.s, `"`) || strings.HasPrefix(p.s, "'") {
+		return p.str()
+	}
+	end := strings.IndexFunc(p.s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' || r == '-')
+	})
+	if end == -1 {
+		end = len(p.s)
+	}
+	if end == 0 {
+		return "", fmt.Errorf("expected a key at %q", p.s)
+	}
+	key := p.s[:end]
+	p.s = p.s[end:]
+	return key, nil
+}
+
+// str parses a basic string, with escapes, or a literal string.
+func (p *tomlParser) str() (string, error) {
+	if strings.HasPrefix(p.s, "'") {
+		end := strings.IndexByte(p.s[1:], '\'')
+		if end == -1 {
+			return "", errors.New("unterminated string")
+		}
+		s := p.s[1 : end+1]
+		p.s = p.s[end+2:]
+		return s, nil
+	}
+	for i := 1; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '\\':
+			i++
+		case '"':
+			s, err := strconv.Unquote(p.s[:i+1])
+			if err != nil {
+				return "", fmt.Errorf("invalid string %s", p.s[:i+1])
+			}
+			p.s = p.s[i+1:]
+			return s, nil
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+// value parses a string, an integer, a boolean or an array of them.
+func (p *tomlParser) value() (any, error) {
+	switch {
+	case strings.HasPrefix(p.s, `"`), strings.HasPrefix(p.s, "'"):
+		return p.str()
+	case strings.HasPrefix(p.s, "["):
+		p.s = p.s[1:]
+		values := []any{}
+		for {
+			p.space()
+			if strings.HasPrefix(p.s, "]") {
+				p.s = p.s[1:]
+				return values, nil
+			}
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			p.space()
+			if strings.HasPrefix(p.s, ",") {
+				p.s = p.s[1:]
+			} else if !strings.HasPrefix(p.s, "]") {
+				return nil, errors.New("expected , or ] in array")
+			}
+		}
+	}
+	end := strings.IndexAny(p.s, " \t\r,]#")
+	if end == -1 {
+		end = len(p.s)
+	}
+	word := p.s[:end]
+	p.s = p.s[end:]
+	switch word {
+	case "":
+		return nil, errors.New("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(word, "_", ""), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value %q (use a string, an integer, a boolean or an array)", word)
+	}
+	return n, nil
+}
@@ -0,0 +1,160 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes in a diff.
+const diffContext = 3
+
+// maxDiffCells bounds the size of the table diffLines compares lines with,
+// beyond which changed lines are all removed and added again.
+const maxDiffCells = 1 << 24
+
+// edit is a line of a diff: kept (' '), removed ('-') or added ('+').
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff turning old, labeled oldName, into
+// new, labeled newName, or "" if they are equal.
+func unifiedDiff(oldName string, old string, newName string, new string) string {
+	if old == new {
+		return ""
+	}
+	edits := diffLines(splitLines(old), splitLines(new))
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	oldLine, newLine := 1, 1 // numbers of the next lines
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+		// Extend the hunk over changes less than two contexts apart
+		start := max(i-diffContext, 0)
+		end := i
+		for kept := 0; end < len(edits) && kept <= 2*diffContext; end++ {
+			if edits[end].op == ' ' {
+				kept++
+			} else {
+				kept = 0
+			}
+		}
+		for end > i && edits[end-1].op == ' ' {
+			end--
+		}
+		end = min(end+diffContext, len(edits))
+		oldStart, newStart := oldLine-(i-start), newLine-(i-start)
+		var oldCount, newCount int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, e := range edits[start:end] {
+			b.WriteByte(e.op)
+			b.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				oldLine++
+			}
+			if e.op != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats the range of lines of a hunk, starting at the line
+// before an empty range like diff -u.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the edits turning the lines old into the lines new,
+// keeping a longest common subsequence of lines.
+func diffLines(old []string, new []string) []edit {
+	var prefix, suffix []edit
+	for len(old) > 0 && len(new) > 0 && old[0] == new[0] {
+		prefix = append(prefix, edit{' ', old[0]})
+		old, new = old[1:], new[1:]
+	}
+	for len(old) > 0 && len(new) > 0 && old[len(old)-1] == new[len(new)-1] {
+		suffix = append(suffix, edit{' ', old[len(old)-1]})
+		old, new = old[:len(old)-1], new[:len(new)-1]
+	}
+	edits := prefix
+	if (len(old)+1)*(len(new)+1) > maxDiffCells {
+		for _, line := range old {
+			edits = append(edits, edit{'-', line})
+		}
+		for _, line := range new {
+			edits = append(edits, edit{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of
+		// old[i:] and new[j:]
+		lcs := make([][]int, len(old)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(new)+1)
+		}
+		for i := len(old) - 1; i >= 0; i-- {
+			for j := len(new) - 1; j >= 0; j-- {
+				if old[i] == new[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(old) || j < len(new) {
+			switch {
+			case i < len(old) && j < len(new) && old[i] == new[j]:
+				edits = append(edits, edit{' ', old[i]})
+				i++
+				j++
+			case j < len(new) && (i == len(old) || lcs[i][j+1] > lcs[i+1][j]):
+				edits = append(edits, edit{'+', new[j]})
+				j++
+			default:
+				edits = append(edits, edit{'-', old[i]})
+				i++
+			}
+		}
+	}
+	for k := len(suffix) - 1; k >= 0; k-- {
+		edits = append(edits, suffix[k])
+	}
+	return edits
+}
@@ -0,0 +1,38 @@
+package gen
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"missing", "", "a\nb\n", "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"orphaned", "a\n", "", "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n"},
+		{
+			"changed",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n",
+			"--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n@@ -14,3 +14,4 @@\n 14\n 15\n 16\n+17\n",
+		},
+		{
+			"merged",
+			"1\n2\n3\n4\n5\n6\n7\n",
+			"0\n1\n2\n3\n4\n5\n6\n",
+			"--- old\n+++ new\n@@ -1,7 +1,7 @@\n+0\n 1\n 2\n 3\n 4\n 5\n 6\n-7\n",
+		},
+		{
+			"separate",
+			"1\n2\n3\n4\n5\n6\n7\n8\n",
+			"0\n1\n2\n3\n4\n5\n6\n7\n",
+			"--- old\n+++ new\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -5,4 +6,3 @@\n 5\n 6\n 7\n-8\n",
+		},
+		{"no newline", "a\nb", "a\nc\n", "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n"},
+	} {
+		if got := unifiedDiff("old", c.old, "new", c.new); got != c.want {
+			t.Errorf("%s: got diff:\n%s\nwant:\n%s", c.name, got, c.want)
+		}
+	}
+}
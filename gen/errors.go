@@ -0,0 +1,104 @@
+package gen
+
+import "fmt"
+
+// unmarshalError returns an expression building the error for the token t
+// that could not be decoded into varExpr, with err as the underlying error
+// ("nil" for a mismatched JSON kind).
+func (g *generator) unmarshalError(varExpr string, errExpr string) string {
+	g.useImports("reflect")
+	return fmt.Sprintf("%s(d, t, reflect.TypeOf(&%s).Elem(), %s)", g.useUnmarshalError(), varExpr, errExpr)
+}
+
+// marshalError returns an expression building the error for varExpr that
+// could not be encoded, with err as the underlying error.
+func (g *generator) marshalError(varExpr string, errExpr string) string {
+	g.useImports("reflect")
+	return fmt.Sprintf("%s(e, reflect.TypeOf(&%s).Elem(), %s)", g.useMarshalError(), varExpr, errExpr)
+}
+
+// useUnmarshalError emits a helper building a *json.SemanticError the way
+// json/v2 reports decode errors, so that callers can inspect the JSON pointer,
+// Go type, JSON kind and byte offset with errors.As, and get the same message.
+// It returns the name of the helper.
+func (g *generator) useUnmarshalError() string {
+	return g.useHelper("unmarshalError", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the error for the token t just read from d that
+			// could not be decoded into a value of type goType.
+			func %[1]s(d *jsontext.Decoder, t jsontext.Token, goType reflect.Type, err error) error {
+				var n int
+				switch t.Kind() {
+				case 'n':
+					n = len("null")
+				case 't':
+					n = len("true")
+				case 'f':
+					n = len("false")
+				case '"':
+					// Exact unless the input escaped more than necessary
+					b, _ := jsontext.AppendQuote(nil, t.String())
+					n = len(b)
+				case '0':
+					n = len(t.String())
+				default:
+					n = len("{")
+				}
+				serr := &json.SemanticError{
+					ByteOffset:  d.InputOffset() - int64(n),
+					JSONPointer: d.StackPointer(),
+					JSONKind:    t.Kind(),
+					GoType:      goType,
+					Err:         err,
+				}
+				var numErr *strconv.NumError
+				if errors.As(err, &numErr) {
+					err = numErr.Err
+					serr.Err = err
+				}
+				if err == strconv.ErrSyntax || err == strconv.ErrRange {
+					// Invalid or out of range numbers report the raw value
+					if t.Kind() == '"' {
+						serr.JSONValue, _ = jsontext.AppendQuote(nil, t.String())
+					} else {
+						serr.JSONValue = jsontext.Value(t.String())
+					}
+				}
+				return serr
+			}
+		`, name))
+	})
+}
+
+// useMarshalError emits a helper building a *json.SemanticError the way
+// json/v2 reports encode errors, pointing at the value about to be written.
+// It returns the name of the helper.
+func (g *generator) useMarshalError() string {
+	return g.useHelper("marshalError", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the error for a value of type goType that could
+			// not be encoded to e.
+			func %[1]s(e *jsontext.Encoder, goType reflect.Type, err error) error {
+				ptr, offset := e.StackPointer(), e.OutputOffset()
+				switch kind, n := e.StackIndex(e.StackDepth()); {
+				case kind == '{' && n%%2 == 1:
+					offset++ // the ':' after the member name
+				case kind == '[':
+					if n > 0 {
+						ptr = ptr.Parent()
+						offset++ // the ',' after the previous element
+					}
+					ptr = ptr.AppendToken(strconv.FormatInt(n, 10))
+				}
+				return &json.SemanticError{
+					ByteOffset:  offset,
+					JSONPointer: ptr,
+					GoType:      goType,
+					Err:         err,
+				}
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,582 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"slices"
+	"strings"
+)
+
+// fallbackTag is the build tag replacing the generated methods with the ones
+// of the fallback files, which encode and decode with json/v2 reflection.
+const fallbackTag = "nogenjson"
+
+// fallbackPath returns the path of the fallback file of the generated file
+// path.
+func fallbackPath(path string) string {
+	return strings.TrimSuffix(path, ".go") + "_fallback.go"
+}
+
+// GenerateFallback writes the methods of the type typeName built with the
+// nogenjson tag instead of the generated ones. They have the same signatures,
+// and hand the value to json/v2 as a type without methods, or as the external
+// type it is declared as, so that it is encoded and decoded with reflection.
+// The options set when generating are passed to json/v2 where it has an
+// equivalent, and limits are enforced by scanning the input first. It returns
+// a warning listing the features of the generated methods the fallback ones
+// lack, if any, or an error if json/v2 cannot stand in for them.
+func (g *generator) GenerateFallback(typeName string, typeExpr ast.Expr) Diagnostics {
+	features := g.fallbackFeatures(typeName, typeExpr)
+	pos := g.fset.Position(g.types[typeName].Name.Pos())
+	if field := features.number; field != nil {
+		return Diagnostics{{
+			Pos:     g.fset.Position(field.Pos()),
+			Message: fmt.Sprintf("%s: the fallback methods built with the %s tag support format:number only in the fields of the types they are generated for and of the types these inline", typeName, fallbackTag),
+		}}
+	}
+	g.useImports("encoding/json/jsontext", "encoding/json/v2")
+	var unmarshalOpts, marshalOpts, unmarshalers, marshalers []string
+	if g.opts.rejectUnknown || g.hasDirective(typeName, "rejectunknown") {
+		unmarshalOpts = append(unmarshalOpts, "json.RejectUnknownMembers(true)")
+	}
+	if g.opts.rejectDuplicates || g.hasDirective(typeName, "rejectduplicates") {
+		unmarshalOpts = append(unmarshalOpts, "jsontext.AllowDuplicateNames(false)")
+	}
+	if g.cfg.IgnoreCase {
+		// Without ignoring underscores and dashes, like encoding/json v1
+		unmarshalOpts = append(unmarshalOpts, "json.MatchCaseInsensitiveNames(true)",
+			g.imports.use("encoding/json")+".MatchCaseSensitiveDelimiter(true)")
+	}
+	if g.cfg.UseNumber {
+		unmarshalers = append(unmarshalers, g.useNumberFallback())
+	}
+	if features.numbers {
+		unmarshalers = append(unmarshalers, g.useNumberUnmarshalers())
+		marshalers = append(marshalers, g.useNumberMarshalers())
+	}
+	if features.bigs {
+		unmarshalers = append(unmarshalers, g.useBigUnmarshalers())
+		marshalers = append(marshalers, g.useBigMarshalers())
+	}
+	if len(unmarshalers) > 0 {
+		// Passed last, since they are joined with the ones of the Decoder
+		unmarshalOpts = append(unmarshalOpts, fmt.Sprintf("%s(d.Options(), %s)", g.useWithUnmarshalers(), strings.Join(unmarshalers, ", ")))
+	}
+	if g.opts.deterministic {
+		marshalOpts = append(marshalOpts, "json.Deterministic(true)")
+	}
+	switch g.cfg.NilSlice {
+	case "null":
+		marshalOpts = append(marshalOpts, "json.FormatNilSliceAsNull(true)")
+	case "empty":
+		marshalOpts = append(marshalOpts, "json.FormatNilSliceAsNull(false)")
+	}
+	if len(marshalers) > 0 {
+		marshalOpts = append(marshalOpts, fmt.Sprintf("%s(e.Options(), %s)", g.useWithMarshalers(), strings.Join(marshalers, ", ")))
+	}
+	args := func(opts []string) string {
+		if len(opts) == 0 {
+			return ""
+		}
+		return ", " + strings.Join(opts, ", ")
+	}
+	plain := "jsonFallback" + typeName
+	conversion := "(*" + plain + ")(p)"
+	if sel, ok := typeExpr.(*ast.SelectorExpr); ok {
+		// e.g. time.Time, whose methods a type declared as it does not have
+		g.useTypeImports(sel)
+		plain = g.typeString(sel)
+		conversion = "(*" + plain + ")(p)"
+	} else if st, ok := typeExpr.(*ast.StructType); ok && g.needsFallbackStruct(st) {
+		// json/v2 does not inline types with methods, nor encode strings as
+		// numbers: the fields of the struct change types, and pointers to it
+		// convert only with unsafe, checked at init to be safe
+		g.useImports("reflect", "unsafe")
+		g.writeLine("")
+		g.writeMultiline(fmt.Sprintf(`
+			// %[2]s has the fields of %[1]s without its methods, of types
+			// json/v2 inlines and encodes like them, and the same layout.
+			type %[2]s %[3]s
+
+			func init() {
+				if !%[4]s(reflect.TypeFor[%[2]s](), reflect.TypeFor[%[1]s]()) {
+					panic("%[5]s.%[1]s does not have the layout of the type its fallback JSON methods use (run go generate)")
+				}
+			}
+		`, typeName, plain, g.fallbackStruct(st), g.useSameLayout(), g.name))
+		conversion = "(*" + plain + ")(unsafe.Pointer(p))"
+	} else {
+		g.writeLine("")
+		g.writeMultiline(fmt.Sprintf(`
+			// %[2]s has the fields of %[1]s without its methods.
+			type %[2]s %[1]s
+		`, typeName, plain))
+	}
+	if !g.opts.marshalOnly {
+		g.writeLine("")
+		if g.cfg.limited() {
+			g.unmarshalerLimited(typeName)
+			g.indent()
+			g.writeLine(fmt.Sprintf("return %s(d, %s, reflect.TypeFor[%s](), limits%s)", g.useDecodeLimited(), conversion, typeName, args(unmarshalOpts)))
+			g.unindent()
+			g.writeLine("}")
+		} else {
+			g.writeMultiline(fmt.Sprintf(`
+				func (p *%[1]s) UnmarshalJSON(b []byte) error {
+					return %[4]s(b, json.Unmarshal(b, p))
+				}
+
+				func (p *%[1]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+					return json.UnmarshalDecode(d, %[2]s%[3]s)
+				}
+			`, typeName, conversion, args(unmarshalOpts), g.usePositionError()))
+		}
+		if g.opts.collect {
+			g.useImports("errors")
+			g.writeLine("")
+			g.writeMultiline(fmt.Sprintf(`
+				// UnmarshalJSONCollect decodes b into p like UnmarshalJSON,
+				// stopping at the first error.
+				func (p *%[1]s) UnmarshalJSONCollect(b []byte) error {
+					return errors.Join(%[2]s(b, json.Unmarshal(b, p)))
+				}
+			`, typeName, g.usePositionError()))
+		}
+	}
+	g.writeLine("")
+	g.writeMultiline(fmt.Sprintf(`
+		func (p *%[1]s) MarshalJSON() ([]byte, error) {
+			return json.Marshal(p)
+		}
+
+		func (p *%[1]s) MarshalJSONTo(e *jsontext.Encoder) error {
+			return json.MarshalEncode(e, %[2]s%[3]s)
+		}
+	`, typeName, conversion, args(marshalOpts)))
+
+	var lacking []string
+	if !g.opts.marshalOnly {
+		if features.strict || g.opts.rejectUnknown || g.opts.rejectDuplicates {
+			// Passed as options, which apply to every nested value
+			lacking = append(lacking, "strictness limited to the types it is set for")
+		}
+		if features.required {
+			lacking = append(lacking, "required members")
+		}
+		if g.opts.collect {
+			lacking = append(lacking, "collecting errors")
+		}
+	}
+	if len(lacking) == 0 {
+		return nil
+	}
+	return Diagnostics{{
+		Pos:      pos,
+		Severity: Warning,
+		Message:  fmt.Sprintf("%s: the fallback methods built with the %s tag lack %s", typeName, fallbackTag, strings.Join(lacking, ", ")),
+	}}
+}
+
+// needsFallbackStruct reports whether the fallback methods of the struct
+// type st need a struct type of their own, whose fields json/v2 inlines or
+// encodes as numbers like the generated methods do.
+func (g *generator) needsFallbackStruct(st *ast.StructType) bool {
+	return g.inlines(st) || slices.ContainsFunc(st.Fields.List, isNumberField)
+}
+
+// inlines reports whether the struct type st has fields whose members are
+// inlined, which are embedded or tagged inline.
+func (g *generator) inlines(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if inlinedType(field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// inlinedType returns the name of the type of field if the members of the
+// struct are inlined, as the generated code does for embedded fields of
+// exported types and fields tagged inline, or "".
+func inlinedType(field *ast.Field) string {
+	jsonTag, jsonOpts := parseTag(field)
+	if jsonTag == "-" || len(field.Names) > 0 && !slices.Contains(jsonOpts, "inline") {
+		return ""
+	}
+	ident, ok := field.Type.(*ast.Ident)
+	if !ok || !ast.IsExported(ident.Name) {
+		return ""
+	}
+	return ident.Name
+}
+
+// isNumberField reports whether field is a string tagged format:number.
+func isNumberField(field *ast.Field) bool {
+	jsonTag, jsonOpts := parseTag(field)
+	return jsonTag != "-" && slices.Contains(jsonOpts, "format:number")
+}
+
+// fallbackStruct returns the struct type with the fields of st, in order so
+// that it has the same layout. The types of inlined fields are replaced by
+// the ones of useFallbackType, which json/v2 inlines, and the strings tagged
+// format:number by the one of useNumberMarshalers, without the option, which
+// json/v2 rejects for strings.
+func (g *generator) fallbackStruct(st *ast.StructType) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, field := range st.Fields.List {
+		var names []string
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		typ := g.typeString(field.Type)
+		tag := ""
+		if field.Tag != nil {
+			tag = " " + field.Tag.Value
+		}
+		switch name := inlinedType(field); {
+		case name != "":
+			typ = g.useFallbackType(name)
+		case isNumberField(field):
+			typ = g.useNumberType()
+			tag = strings.Replace(tag, ",format:number", "", 1)
+		default:
+			g.useTypeImports(field.Type)
+		}
+		b.WriteString("\t" + strings.Join(append(names, typ), " ") + tag + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// useFallbackType emits the type json/v2 encodes and decodes the inlined
+// struct type typeName as, which has its fields without its methods. It
+// returns the name of the type.
+func (g *generator) useFallbackType(typeName string) string {
+	return g.useHelper("jsonFallback"+typeName, func(h *generator, name string) {
+		defer h.inFile(typeName)()
+		if st, ok := h.types[typeName].Type.(*ast.StructType); ok && h.needsFallbackStruct(st) {
+			h.writeMultiline(fmt.Sprintf(`
+				// %[2]s has the fields of %[1]s without its methods, of
+				// types json/v2 inlines and encodes like them.
+				type %[2]s %[3]s
+			`, typeName, name, h.fallbackStruct(st)))
+			return
+		}
+		h.writeMultiline(fmt.Sprintf(`
+			// %[2]s has the fields of %[1]s without its methods.
+			type %[2]s %[1]s
+		`, typeName, name))
+	})
+}
+
+// fallbackFeatures are the features of the methods generated for a type that
+// its fallback methods handle or lack.
+type fallbackFeatures struct {
+	strict   bool // whether a nested type rejects unknown or duplicate names
+	required bool // whether a member is required
+	numbers  bool // whether strings tagged format:number are encoded as numbers
+	bigs     bool // whether big.Float or big.Rat values are encoded as numbers
+
+	// number is a string tagged format:number which json/v2 decodes without
+	// the fallback methods, and rejects, if any
+	number *ast.Field
+}
+
+// fallbackFeatures returns the features of the methods generated for the
+// type typeName declared as typeExpr.
+func (g *generator) fallbackFeatures(typeName string, typeExpr ast.Expr) fallbackFeatures {
+	var (
+		f        fallbackFeatures
+		visiting = make(map[string]bool)
+		walk     func(typeName string, expr ast.Expr, inlined bool)
+	)
+	walk = func(typeName string, expr ast.Expr, inlined bool) {
+		switch ts := expr.(type) {
+		case *ast.Ident:
+			typeSpec, ok := g.types[ts.Name]
+			if !ok || visiting[ts.Name] {
+				return
+			}
+			visiting[ts.Name] = true
+			defer g.inFile(ts.Name)()
+			walk(ts.Name, typeSpec.Type, inlined)
+		case *ast.SelectorExpr:
+			if x, ok := ts.X.(*ast.Ident); ok {
+				switch g.importPath(x) + "." + ts.Sel.Name {
+				case "math/big.Float", "math/big.Rat":
+					f.bigs = true
+				}
+			}
+		case *ast.StarExpr:
+			walk("", ts.X, false)
+		case *ast.ArrayType:
+			walk("", ts.Elt, false)
+		case *ast.MapType:
+			walk("", ts.Value, false)
+		case *ast.StructType:
+			f.strict = f.strict || g.hasDirective(typeName, "rejectunknown") || g.hasDirective(typeName, "rejectduplicates")
+			for _, m := range g.jsonMembers(ts, g.hasDirective(typeName, "required")) {
+				f.required = f.required || m.required
+			}
+			for _, field := range ts.Fields.List {
+				if jsonTag, _ := parseTag(field); jsonTag == "-" {
+					continue
+				}
+				if isNumberField(field) {
+					if inlined {
+						f.numbers = true
+					} else if f.number == nil && !g.hasFallback(typeName) {
+						f.number = field
+					}
+				}
+				walk("", field.Type, inlined && inlinedType(field) != "")
+			}
+		}
+	}
+	walk(typeName, typeExpr, true)
+	return f
+}
+
+// hasFallback reports whether the type typeName has fallback methods of its
+// own, being generated along with the current one or marked.
+func (g *generator) hasFallback(typeName string) bool {
+	if typeName == "" {
+		return false
+	}
+	if g.fallbacks[typeName] {
+		return true
+	}
+	_, ok := marked(g.types[typeName])
+	return ok
+}
+
+// useNumberFallback emits the json/v2 unmarshalers decoding numbers in any
+// values as json.Number, which json/v2 has no option for. Values that are
+// not nil are left to json/v2, which merges into them. It returns the name
+// of the variable holding them.
+func (g *generator) useNumberFallback() string {
+	return g.useHelper("useNumber", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s decodes numbers in any values as json.Number.
+			var %[1]s = json.UnmarshalFromFunc(func(d *jsontext.Decoder, v *any) error {
+				if *v != nil || d.PeekKind() != '0' {
+					return errors.ErrUnsupported
+				}
+				t, err := d.ReadToken()
+				if err != nil {
+					return err
+				}
+				*v = %[2]s.Number(t.String())
+				return nil
+			})
+		`, name, h.imports.use("encoding/json")))
+	})
+}
+
+// useNumberType emits the type of the fallback fields of strings tagged
+// format:number. It returns the name of the type.
+func (g *generator) useNumberType() string {
+	return g.useHelper("jsonFallbackNumber", func(h *generator, name string) {
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s is a string tagged format:number, encoded as a JSON number
+			// by the fallback marshalers and unmarshalers.
+			type %[1]s string
+		`, name))
+	})
+}
+
+// useNumberUnmarshalers emits the json/v2 unmarshalers decoding the strings
+// tagged format:number of fallback structs like the generated methods. It
+// returns the name of the variable holding them.
+func (g *generator) useNumberUnmarshalers() string {
+	return g.useHelper("numberUnmarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s decode JSON numbers, and strings holding one, into
+			// %[2]s values.
+			var %[1]s = json.UnmarshalFromFunc(func(d *jsontext.Decoder, v *%[2]s) error {
+				t, err := d.ReadToken()
+				if err != nil {
+					return err
+				}
+				switch t.Kind() {
+				case 'n':
+					if legacy, _ := json.GetOption(d.Options(), %[4]s.MergeWithLegacySemantics); !legacy {
+						*v = ""
+					}
+				case '0':
+					*v = %[2]s(t.String())
+				case '"':
+					s := t.String()
+					if !%[3]s(s) {
+						return &json.SemanticError{JSONKind: '"', GoType: reflect.TypeFor[string](), Err: errors.New("invalid number literal: " + strconv.Quote(s))}
+					}
+					*v = %[2]s(s)
+				default:
+					return &json.SemanticError{JSONKind: t.Kind(), GoType: reflect.TypeFor[string]()}
+				}
+				return nil
+			})
+		`, name, h.useNumberType(), h.useIsNumber(), h.imports.use("encoding/json")))
+	})
+}
+
+// useNumberMarshalers emits the json/v2 marshalers encoding the strings
+// tagged format:number of fallback structs like the generated methods. It
+// returns the name of the variable holding them.
+func (g *generator) useNumberMarshalers() string {
+	return g.useHelper("numberMarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s encode %[2]s values as JSON numbers, 0 if empty.
+			var %[1]s = json.MarshalToFunc(func(e *jsontext.Encoder, v *%[2]s) error {
+				switch s := string(*v); {
+				case s == "":
+					return e.WriteToken(jsontext.Int(0))
+				case !%[3]s(s):
+					return &json.SemanticError{GoType: reflect.TypeFor[string](), Err: errors.New("invalid number literal: " + strconv.Quote(s))}
+				default:
+					return e.WriteValue(jsontext.Value(s))
+				}
+			})
+		`, name, h.useNumberType(), h.useIsNumber()))
+	})
+}
+
+// useBigUnmarshalers emits the json/v2 unmarshalers decoding big.Float and
+// big.Rat values from JSON numbers like the generated methods, where json/v2
+// expects strings. It returns the name of the variable holding them.
+func (g *generator) useBigUnmarshalers() string {
+	return g.useHelper("bigUnmarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "math/big", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s decode big.Float and big.Rat values from JSON numbers.
+			var %[1]s = json.JoinUnmarshalers(
+				json.UnmarshalFromFunc(func(d *jsontext.Decoder, v *big.Float) error {
+					t, err := d.ReadToken()
+					if err != nil {
+						return err
+					}
+					if t.Kind() != '0' {
+						return &json.SemanticError{JSONKind: t.Kind(), GoType: reflect.TypeFor[big.Float]()}
+					}
+					if _, _, err := v.SetPrec(max(64, 4*uint(len(t.String())))).Parse(t.String(), 10); err != nil {
+						return &json.SemanticError{JSONKind: '0', GoType: reflect.TypeFor[big.Float](), Err: err}
+					}
+					return nil
+				}),
+				json.UnmarshalFromFunc(func(d *jsontext.Decoder, v *big.Rat) error {
+					t, err := d.ReadToken()
+					if err != nil {
+						return err
+					}
+					if t.Kind() != '0' {
+						return &json.SemanticError{JSONKind: t.Kind(), GoType: reflect.TypeFor[big.Rat]()}
+					}
+					if _, ok := v.SetString(t.String()); !ok {
+						return &json.SemanticError{JSONKind: '0', GoType: reflect.TypeFor[big.Rat](), Err: strconv.ErrSyntax}
+					}
+					return nil
+				}),
+			)
+		`, name))
+	})
+}
+
+// useBigMarshalers emits the json/v2 marshalers encoding big.Float and
+// big.Rat values as JSON numbers like the generated methods, where json/v2
+// encodes strings. It returns the name of the variable holding them.
+func (g *generator) useBigMarshalers() string {
+	return g.useHelper("bigMarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "math/big", "reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s encode big.Float and big.Rat values as JSON numbers.
+			var %[1]s = json.JoinMarshalers(
+				json.MarshalToFunc(func(e *jsontext.Encoder, v *big.Float) error {
+					if v.IsInf() {
+						return &json.SemanticError{GoType: reflect.TypeFor[big.Float](), Err: errors.New("unsupported value: " + v.String())}
+					}
+					return e.WriteValue(v.Append(e.AvailableBuffer(), 'g', -1))
+				}),
+				json.MarshalToFunc(func(e *jsontext.Encoder, v *big.Rat) error {
+					n, exact := v.FloatPrec()
+					if !exact {
+						return &json.SemanticError{GoType: reflect.TypeFor[big.Rat](), Err: errors.New("unsupported value: " + v.String() + " has no finite decimal representation")}
+					}
+					return e.WriteValue(jsontext.Value(v.FloatString(n)))
+				}),
+			)
+		`, name))
+	})
+}
+
+// useWithUnmarshalers emits a helper returning the option decoding with
+// unmarshalers before the ones already set. It returns the name of the
+// helper.
+func (g *generator) useWithUnmarshalers() string {
+	return g.useHelper("withUnmarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/v2")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the option decoding with u, then with the
+			// unmarshalers of opts.
+			func %[1]s(opts json.Options, u ...*json.Unmarshalers) json.Options {
+				if others, _ := json.GetOption(opts, json.WithUnmarshalers); others != nil {
+					u = append(u, others)
+				}
+				return json.WithUnmarshalers(json.JoinUnmarshalers(u...))
+			}
+		`, name))
+	})
+}
+
+// useWithMarshalers emits a helper returning the option encoding with
+// marshalers before the ones already set. It returns the name of the helper.
+func (g *generator) useWithMarshalers() string {
+	return g.useHelper("withMarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/v2")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the option encoding with m, then with the
+			// marshalers of opts.
+			func %[1]s(opts json.Options, m ...*json.Marshalers) json.Options {
+				if others, _ := json.GetOption(opts, json.WithMarshalers); others != nil {
+					m = append(m, others)
+				}
+				return json.WithMarshalers(json.JoinMarshalers(m...))
+			}
+		`, name))
+	})
+}
+
+// useSameLayout emits a helper reporting whether two types have the same
+// layout, so that pointers to one convert to pointers to the other with
+// unsafe. It returns the name of the helper.
+func (g *generator) useSameLayout() string {
+	return g.useHelper("sameLayout", func(h *generator, name string) {
+		h.useImports("reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s reports whether a and b have the same size, and the same
+			// fields at the same offsets, of types with the same layout or
+			// convertible to each other.
+			func %[1]s(a, b reflect.Type) bool {
+				if a.Size() != b.Size() || a.Kind() != b.Kind() {
+					return false
+				}
+				if a.Kind() != reflect.Struct {
+					return a.ConvertibleTo(b)
+				}
+				if a.NumField() != b.NumField() {
+					return false
+				}
+				for i := range a.NumField() {
+					fa, fb := a.Field(i), b.Field(i)
+					if fa.Offset != fb.Offset || !%[1]s(fa.Type, fb.Type) {
+						return false
+					}
+				}
+				return true
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,141 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"errors"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFallback(t *testing.T) {
+	files, err := Generate(t.Context(), Config{Dir: "testdata/imports", Types: []string{"Imports"}, Options: Options{Fallback: true, Collect: true, Limits: true}})
+	var diags Diagnostics
+	if !errors.As(err, &diags) || diags.HasErrors() {
+		t.Fatalf("got error %v, want warnings", err)
+	}
+	if want := "Imports: the fallback methods built with the nogenjson tag lack collecting errors"; len(diags) != 1 || diags[0].Message != want {
+		t.Errorf("got diagnostics %v, want a warning %q", diags, want)
+	}
+	dir, err := filepath.Abs("testdata/imports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := filepath.Join(dir, "imports_gen_json.go")
+	fallback := filepath.Join(dir, "imports_gen_json_fallback.go")
+	// Both builds share the limits
+	limits := filepath.Join(dir, "jsonlimits_gen_json.go")
+	if got, want := slices.Sorted(maps.Keys(files)), []string{generated, fallback, limits}; !slices.Equal(got, want) {
+		t.Fatalf("got files %v, want %v", got, want)
+	}
+	if bytes.Contains(files[limits], []byte("//go:build")) {
+		t.Errorf("%s has a build constraint", filepath.Base(limits))
+	}
+	for path, want := range map[string][]string{
+		generated: {"//go:build !nogenjson\n"},
+		fallback: {
+			"//go:build nogenjson\n",
+			"func (p *Imports) UnmarshalJSONFrom(d *jsontext.Decoder) error {",
+			"func (p *Imports) UnmarshalJSONCollect(b []byte) error {",
+			"func (p *Imports) UnmarshalJSONLimits(b []byte, limits JSONLimits, opts ...json.Options) error {",
+			"func (p *Imports) MarshalJSONTo(e *jsontext.Encoder) error {",
+		},
+	} {
+		for _, want := range want {
+			if !bytes.Contains(files[path], []byte(want)) {
+				t.Errorf("%s lacks %q", filepath.Base(path), want)
+			}
+		}
+	}
+
+	// Without the option, files are built whatever the tags
+	files, err = Generate(t.Context(), Config{Dir: "testdata/imports", Types: []string{"Imports"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || bytes.Contains(files[generated], []byte("//go:build")) {
+		t.Errorf("got files %v with build constraints, want %s without", slices.Sorted(maps.Keys(files)), generated)
+	}
+
+	// Strings tagged format:number are encoded as numbers by the fallback
+	// methods of the type declaring them only
+	_, err = Generate(t.Context(), Config{Dir: "testdata/fallback", Types: []string{"Amounts"}, Options: Options{Fallback: true}})
+	want := "Amounts: the fallback methods built with the nogenjson tag support format:number only in the fields of the types they are generated for and of the types these inline"
+	if !errors.As(err, &diags) || len(diags) != 1 || diags[0].Message != want || diags[0].Pos.Line != 6 {
+		t.Errorf("got error %v, want an error at line 6: %s", err, want)
+	}
+}
+
+// testEvent is an event of go test -json.
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// elapsed matches the durations of the results of tests.
+var elapsed = regexp.MustCompile(`\([0-9.]+s\)`)
+
+// runExamples runs the tests of the examples with the build tags, and returns
+// the output of each test that did not skip.
+func runExamples(t *testing.T, tags string) map[string]string {
+	t.Helper()
+	cmd := exec.Command("go", "test", "-count=1", "-json", "-tags="+tags, ".")
+	cmd.Dir = "../examples"
+	out, err := cmd.Output()
+	outputs := make(map[string]string)
+	skipped := make(map[string]bool)
+	var failed []string
+	for line := range bytes.Lines(out) {
+		var e testEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatalf("tags %q: %v: %s", tags, err, line)
+		}
+		switch {
+		case e.Test == "":
+		case e.Action == "output":
+			outputs[e.Test] += elapsed.ReplaceAllString(e.Output, "")
+		case e.Action == "skip":
+			skipped[e.Test] = true
+		case e.Action == "fail":
+			failed = append(failed, e.Test)
+		}
+	}
+	if err != nil {
+		t.Fatalf("tags %q: %v; failed tests: %s", tags, err, strings.Join(failed, ", "))
+	}
+	for test := range skipped {
+		delete(outputs, test)
+	}
+	return outputs
+}
+
+// TestFallback runs the tests of the examples with the generated files and
+// with the fallback files, and compares the outputs of the tests run by both.
+func TestFallback(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping fallback test in short mode")
+	}
+	generated := runExamples(t, "")
+	fallback := runExamples(t, fallbackTag)
+	var compared int
+	for _, test := range slices.Sorted(maps.Keys(generated)) {
+		out, ok := fallback[test]
+		if !ok {
+			continue
+		}
+		compared++
+		if diff := unifiedDiff(test, generated[test], test+" ("+fallbackTag+")", out); diff != "" {
+			t.Errorf("outputs differ:\n%s", diff)
+		}
+	}
+	if compared == 0 {
+		t.Error("no test of the examples ran with both the generated and the fallback files")
+	}
+	t.Logf("compared %d tests", compared)
+}
@@ -0,0 +1,234 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The fingerprint of a type is the FNV-1a hash of its shape: its fields with
+// their names, types and tags. The types declared in its package are expanded
+// as "Name=" followed by their underlying type, and other named types are
+// written as pkg.Name. The generator computes the shape from the declaration,
+// and the generated code from reflect.Type, so both must describe types the
+// same way.
+
+// GenerateFingerprint writes the fingerprint of the type typeName, and an
+// init function panicking if the type has changed since, when the methods
+// generated for its previous shape would decode and encode it wrongly.
+func (g *generator) GenerateFingerprint(typeName string) {
+	g.useImports("reflect")
+	var shape strings.Builder
+	g.shape(&shape, &ast.Ident{Name: typeName})
+	h := fnv.New64a()
+	h.Write([]byte(shape.String()))
+	g.writeLine("")
+	g.writeMultiline(fmt.Sprintf(`
+		// jsonFingerprint%[1]s is the fingerprint of the fields of %[1]s, with
+		// their types and tags, that its JSON methods were generated for.
+		const jsonFingerprint%[1]s = %[2]q
+
+		func init() {
+			if %[3]s(reflect.TypeFor[%[1]s]()) != jsonFingerprint%[1]s {
+				panic("%[4]s.%[1]s has changed since its JSON methods were generated (run go generate)")
+			}
+		}
+	`, typeName, strconv.FormatUint(h.Sum64(), 16), g.useFingerprint(), g.name))
+}
+
+// shape writes the shape of the type expr, resolving package names in the
+// current file.
+func (g *generator) shape(b *strings.Builder, expr ast.Expr) {
+	switch ts := expr.(type) {
+	case *ast.Ident:
+		if typeSpec, ok := g.types[ts.Name]; ok {
+			b.WriteString(ts.Name + "=")
+			defer g.inFile(ts.Name)()
+			g.structure(b, typeSpec.Type)
+			return
+		}
+		b.WriteString(predeclaredShape(ts.Name))
+	case *ast.SelectorExpr:
+		if x, ok := ts.X.(*ast.Ident); ok {
+			b.WriteString(packageName(g.importPath(x)) + "." + ts.Sel.Name)
+			return
+		}
+		b.WriteString(exprToString(ts))
+	case *ast.ParenExpr:
+		g.shape(b, ts.X)
+	default:
+		g.structure(b, expr)
+	}
+}
+
+// structure writes the shape of the underlying type of the type expr, which
+// reflection sees without the names of the types declaring it.
+func (g *generator) structure(b *strings.Builder, expr ast.Expr) {
+	switch ts := expr.(type) {
+	case *ast.Ident:
+		if typeSpec, ok := g.types[ts.Name]; ok {
+			defer g.inFile(ts.Name)()
+			g.structure(b, typeSpec.Type)
+			return
+		}
+		b.WriteString(predeclaredShape(ts.Name))
+	case *ast.SelectorExpr:
+		// Only the external types supported by the generator are known:
+		// json.Number is a string, and the others structs with unexported
+		// fields
+		if x, ok := ts.X.(*ast.Ident); ok && g.importPath(x)+"."+ts.Sel.Name == "encoding/json.Number" {
+			b.WriteString("string")
+			return
+		}
+		b.WriteString("external")
+	case *ast.ParenExpr:
+		g.structure(b, ts.X)
+	case *ast.StarExpr:
+		b.WriteString("*")
+		g.shape(b, ts.X)
+	case *ast.ArrayType:
+		if ts.Len == nil {
+			b.WriteString("[]")
+		} else {
+			b.WriteString("[" + exprToString(ts.Len) + "]")
+		}
+		g.shape(b, ts.Elt)
+	case *ast.MapType:
+		b.WriteString("map[")
+		g.shape(b, ts.Key)
+		b.WriteString("]")
+		g.shape(b, ts.Value)
+	case *ast.StructType:
+		b.WriteString("struct{")
+		first := true
+		for _, field := range ts.Fields.List {
+			var tag string
+			if field.Tag != nil {
+				tag, _ = strconv.Unquote(field.Tag.Value)
+			}
+			names := []string{"embedded"}
+			if len(field.Names) > 0 {
+				names = names[:0]
+				for _, name := range field.Names {
+					names = append(names, name.Name)
+				}
+			}
+			for _, name := range names {
+				if !first {
+					b.WriteString("; ")
+				}
+				first = false
+				b.WriteString(name + " ")
+				// The types of ignored fields do not matter
+				if reflect.StructTag(tag).Get("json") == "-" {
+					b.WriteString("-")
+				} else {
+					g.shape(b, field.Type)
+				}
+				if tag != "" {
+					b.WriteString(" " + strconv.Quote(tag))
+				}
+			}
+		}
+		b.WriteString("}")
+	case *ast.InterfaceType:
+		if len(ts.Methods.List) == 0 {
+			b.WriteString("any")
+			return
+		}
+		b.WriteString(exprToString(ts))
+	default:
+		b.WriteString(exprToString(expr))
+	}
+}
+
+// useFingerprint emits a helper computing the fingerprint of a type with
+// reflection, like GenerateFingerprint does from its declaration. It returns
+// the name of the helper.
+func (g *generator) useFingerprint() string {
+	return g.useHelper("shapeFingerprint", func(h *generator, name string) {
+		h.useImports("hash/fnv", "io", "reflect", "strconv")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the fingerprint of the shape of t, expanding the
+			// types declared in its package.
+			func %[1]s(t reflect.Type) string {
+				h := fnv.New64a()
+				pkgPath := t.PkgPath()
+				var shape, structure func(t reflect.Type)
+				shape = func(t reflect.Type) {
+					switch {
+					case t.Name() == "":
+						structure(t)
+					case t.PkgPath() == "":
+						io.WriteString(h, t.Name())
+					case t.PkgPath() == pkgPath:
+						io.WriteString(h, t.Name()+"=")
+						structure(t)
+					default:
+						io.WriteString(h, t.String())
+					}
+				}
+				structure = func(t reflect.Type) {
+					switch t.Kind() {
+					case reflect.Pointer:
+						io.WriteString(h, "*")
+						shape(t.Elem())
+					case reflect.Slice:
+						io.WriteString(h, "[]")
+						shape(t.Elem())
+					case reflect.Array:
+						io.WriteString(h, "["+strconv.Itoa(t.Len())+"]")
+						shape(t.Elem())
+					case reflect.Map:
+						io.WriteString(h, "map[")
+						shape(t.Key())
+						io.WriteString(h, "]")
+						shape(t.Elem())
+					case reflect.Struct:
+						for i := range t.NumField() {
+							if f := t.Field(i); f.PkgPath != "" && f.PkgPath != pkgPath {
+								// Declared by another package
+								io.WriteString(h, "external")
+								return
+							}
+						}
+						io.WriteString(h, "struct{")
+						for i := range t.NumField() {
+							f := t.Field(i)
+							if i > 0 {
+								io.WriteString(h, "; ")
+							}
+							if f.Anonymous {
+								io.WriteString(h, "embedded ")
+							} else {
+								io.WriteString(h, f.Name+" ")
+							}
+							if f.Tag.Get("json") == "-" {
+								io.WriteString(h, "-")
+							} else {
+								shape(f.Type)
+							}
+							if f.Tag != "" {
+								io.WriteString(h, " "+strconv.Quote(string(f.Tag)))
+							}
+						}
+						io.WriteString(h, "}")
+					case reflect.Interface:
+						if t.NumMethod() == 0 {
+							io.WriteString(h, "any")
+						} else {
+							io.WriteString(h, t.String())
+						}
+					default:
+						io.WriteString(h, t.Kind().String())
+					}
+				}
+				shape(t)
+				return strconv.FormatUint(h.Sum64(), 16)
+			}
+		`, name))
+	})
+}
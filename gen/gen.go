@@ -0,0 +1,421 @@
+// Package gen generates JSON encoding and decoding methods for Go types,
+// using encoding/json/v2 and jsontext without reflection. It is the library
+// behind the go-gen-json command, for build tools and tests that generate
+// in-process.
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Config selects the types to generate methods for, and how.
+//
+// The settings of the gen-json.toml or gen-json.json file nearest to the
+// directory of each package, in it or in a parent up to the module root,
+// override Options, and Output and Tags if they are empty. The build tags
+// are read from the file nearest to Dir, since packages are loaded with them.
+type Config struct {
+	Dir          string         // directory patterns are resolved in, the current one if empty
+	Patterns     []string       // patterns of the packages declaring the types, "." if empty
+	Tags         string         // comma-separated build tags to load the packages with
+	Types        []string       // names of the types to generate, the marked types if empty
+	All          bool           // generate every exported struct type instead of Types
+	Output       string         // file of each package all its types are generated into, or its name with {type} for each type; <type>_gen_json.go for each if empty
+	Options      Options        // options of the generated methods
+	Override     func(*Options) // applied to the options of each package after its configuration file, like command-line flags
+	NoConfigFile bool           // ignore configuration files
+}
+
+// Options are the options the methods of every type are generated with.
+// The options of the marker of a type override RejectUnknown,
+// RejectDuplicates, Deterministic, MarshalOnly and Collect.
+type Options struct {
+	Debug            bool // log generation, and emit code logging decoding
+	UseNumber        bool // decode numbers in any values as json.Number
+	IgnoreCase       bool // match member names case-insensitively like encoding/json v1
+	RejectUnknown    bool // reject unknown object members regardless of json.RejectUnknownMembers
+	RejectDuplicates bool // reject duplicate member names regardless of jsontext.AllowDuplicateNames
+	Collect          bool // generate UnmarshalJSONCollect, reporting all semantic errors at once
+	Deterministic    bool // encode maps in sorted key order regardless of json.Deterministic
+	MarshalOnly      bool // generate the encoding methods only
+	Limits           bool // generate UnmarshalJSONLimits and enforce limits, implied by any Max limit
+	Fingerprint      bool // embed the fingerprint of each type, checked against the type in an init function
+	Fallback         bool // build generated files without the nogenjson tag, and fallback files using reflection with it
+
+	// NilSlice is how nil slices are encoded: "null" or "empty" (as [])
+	// regardless of json.FormatNilSliceAsNull, which is honored if empty.
+	NilSlice string
+
+	// TimeFormat is the layout time.Time values are encoded and decoded
+	// with, as accepted by time.Format, or the name of a layout constant of
+	// package time such as "RFC1123" or "DateOnly". RFC 3339 if empty.
+	TimeFormat string
+
+	// Codecs map external types, as "import/path.Name", to how they are
+	// encoded and decoded: "text" with their MarshalText and UnmarshalText
+	// methods as JSON strings, or "json" with json.MarshalEncode and
+	// json.UnmarshalDecode, that is by json/v2 itself.
+	Codecs map[string]string
+
+	// Limits bound the input accepted by generated decoders, so that hostile
+	// payloads are rejected before they exhaust memory. They are the defaults
+	// of UnmarshalJSON and UnmarshalJSONFrom, and UnmarshalJSONLimits takes
+	// others per call. Zero is unlimited.
+	MaxDepth        int // nesting depth of objects and arrays
+	MaxBytes        int // size of the input
+	MaxElements     int // elements per array
+	MaxMembers      int // members per object
+	MaxStringLength int // bytes per string, member names included
+}
+
+// Severity is how serious a Diagnostic is.
+type Severity int
+
+const (
+	Error   Severity = iota // the problem prevents generation
+	Warning                 // the code is generated, but may not do what is meant
+)
+
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "error"
+}
+
+// MarshalText encodes the severity as "error" or "warning".
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem with the types to generate, located in the package
+// sources when Pos is valid: at the offending field, type expression or
+// declaration.
+type Diagnostic struct {
+	Pos      token.Position
+	Severity Severity
+	Message  string
+}
+
+// String formats the diagnostic as "file:line:col: message", with
+// "warning: " before the message of warnings.
+func (d Diagnostic) String() string {
+	msg := d.Message
+	if d.Severity == Warning {
+		msg = "warning: " + msg
+	}
+	if !d.Pos.IsValid() {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", d.Pos, msg)
+}
+
+// Diagnostics are the problems returned by Generate, one per line.
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasErrors reports whether any of the diagnostics is an error.
+func (ds Diagnostics) HasErrors() bool {
+	return slices.ContainsFunc(ds, func(d Diagnostic) bool { return d.Severity == Error })
+}
+
+// Generate generates the methods of the types selected by cfg, and returns
+// the contents of the generated files by path, formatted like gofmt. Nothing
+// is written. Problems with the packages, types or options are returned as
+// Diagnostics, collected across all the types. If they are all warnings, the
+// files are returned along with them; otherwise no files are.
+func Generate(ctx context.Context, cfg Config) (map[string][]byte, error) {
+	files, _, err := generate(ctx, cfg)
+	return files, err
+}
+
+// generate is like Generate, and also returns the packages loaded.
+func generate(ctx context.Context, cfg Config) (files map[string][]byte, pkgs []*Package, err error) {
+	if err := cfg.check(); err != nil {
+		return nil, nil, err
+	}
+	patterns := cfg.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	tags := cfg.Tags
+	if tags == "" && !cfg.NoConfigFile {
+		s, err := cfg.settings(cfg.Dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags = strings.Join(s.Tags, ",")
+	}
+	pkgs, err = LoadPackages(ctx, cfg.Dir, patterns, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	units, err := cfg.units(pkgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Validate every type before generating any
+	var diags Diagnostics
+	for _, u := range units {
+		diags = append(diags, u.g.validate(u.typeSpecs)...)
+	}
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+	// Problems the validation missed stop the generation of their type only,
+	// so that the problems of the others are reported too
+	files = make(map[string][]byte)
+	for _, u := range units {
+		failed := false
+		for i, typeSpec := range u.typeSpecs {
+			if i > 0 {
+				u.g.body.WriteString("\n")
+			}
+			u.g.typeName = typeSpec.Name.Name
+			u.g.file = u.p.Files[u.g.typeName]
+			if d := u.g.catch(typeSpec, func() { u.g.generate(typeSpec) }); d != nil {
+				diags = append(diags, *d)
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+		if u.g.cfg.Fallback {
+			u.g.build = "!" + fallbackTag
+			fb := newGenerator(u.p, u.g.typeName, u.g.cfg)
+			fb.build = fallbackTag
+			fb.suffix = u.g.suffix
+			fb.fallbacks = make(map[string]bool)
+			for _, typeSpec := range u.typeSpecs {
+				fb.fallbacks[typeSpec.Name.Name] = true
+			}
+			for i, typeSpec := range u.typeSpecs {
+				if i > 0 {
+					fb.body.WriteString("\n")
+				}
+				fb.typeName = typeSpec.Name.Name
+				fb.file = u.p.Files[fb.typeName]
+				opts, problem := fb.typeOptions(typeSpec)
+				if problem != nil {
+					diags = append(diags, *problem)
+					continue
+				}
+				fb.opts = opts
+				diags = append(diags, fb.GenerateFallback(typeSpec.Name.Name, typeSpec.Type)...)
+			}
+			if files[fallbackPath(u.path)], err = fb.source(); err != nil {
+				return nil, nil, err
+			}
+		}
+		if files[u.path], err = u.g.source(); err != nil {
+			return nil, nil, err
+		}
+		if u.g.cfg.limited() {
+			if files[filepath.Join(u.p.Dir, limitsFileName)], err = limitsSource(u.p); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+	if len(diags) > 0 {
+		return files, pkgs, diags
+	}
+	return files, pkgs, nil
+}
+
+// check reports invalid combinations of settings.
+func (cfg *Config) check() error {
+	if cfg.All && len(cfg.Types) > 0 {
+		return errors.New("Types and All are mutually exclusive")
+	}
+	return nil
+}
+
+// check reports invalid options.
+func (o *Options) check() error {
+	for _, limit := range []int{o.MaxDepth, o.MaxBytes, o.MaxElements, o.MaxMembers, o.MaxStringLength} {
+		if limit < 0 {
+			return fmt.Errorf("negative limit: %d", limit)
+		}
+	}
+	switch o.NilSlice {
+	case "", "null", "empty":
+	default:
+		return fmt.Errorf("invalid nil slice format %q (use null or empty)", o.NilSlice)
+	}
+	if strings.HasPrefix(o.TimeFormat, "unix") {
+		return fmt.Errorf("unsupported time format %q (use a layout)", o.TimeFormat)
+	}
+	for typ, codec := range o.Codecs {
+		if dot := strings.LastIndexByte(typ, '.'); dot <= 0 || dot == len(typ)-1 || strings.ContainsRune(typ[dot:], '/') {
+			return fmt.Errorf("invalid codec type %q (use import/path.Name)", typ)
+		}
+		if codec != "text" && codec != "json" {
+			return fmt.Errorf("invalid codec %q of %s (use text or json)", codec, typ)
+		}
+	}
+	return nil
+}
+
+// settings returns the settings of the configuration file nearest to the
+// directory dir, empty if there is none.
+func (cfg *Config) settings(dir string) (*settings, error) {
+	path, err := findConfigFile(dir)
+	if err != nil {
+		return nil, Diagnostics{{Message: err.Error()}}
+	}
+	if path == "" {
+		return &settings{}, nil
+	}
+	s, problem := readConfigFile(path)
+	if problem != nil {
+		return nil, Diagnostics{*problem}
+	}
+	return s, nil
+}
+
+// packageOptions returns the options of package p and the file its types are
+// generated into, with the settings of its configuration file.
+func (cfg *Config) packageOptions(p *Package) (*Options, string, error) {
+	opts := cfg.Options
+	output := cfg.Output
+	if !cfg.NoConfigFile {
+		s, err := cfg.settings(p.Dir)
+		if err != nil {
+			return nil, "", err
+		}
+		s.apply(&opts)
+		if output == "" && s.Output != nil {
+			output = *s.Output
+		}
+	}
+	if cfg.Override != nil {
+		cfg.Override(&opts)
+	}
+	if err := opts.check(); err != nil {
+		return nil, "", err
+	}
+	return &opts, output, nil
+}
+
+// unit is a file to generate: the methods of types of a package.
+type unit struct {
+	p         *Package
+	typeSpecs []*ast.TypeSpec
+	path      string
+	g         *generator
+}
+
+// units finds the types selected by cfg in the packages pkgs, and returns the
+// files to generate them into.
+func (cfg *Config) units(pkgs []*Package) ([]unit, error) {
+	found := make(map[string]bool)
+	var units []unit
+	for _, p := range pkgs {
+		names := cfg.Types
+		switch {
+		case cfg.All:
+			names = ExportedStructs(p)
+		case len(cfg.Types) == 0:
+			names = MarkedTypes(p)
+		}
+		var typeSpecs []*ast.TypeSpec
+		for _, typeName := range names {
+			if typeSpec, ok := p.Types[typeName]; ok {
+				typeSpecs = append(typeSpecs, typeSpec)
+				found[typeName] = true
+			}
+		}
+		if len(typeSpecs) == 0 {
+			continue
+		}
+		opts, output, err := cfg.packageOptions(p)
+		if err != nil {
+			return nil, err
+		}
+		if output != "" && !strings.Contains(output, "{type}") {
+			g := newGenerator(p, typeSpecs[0].Name.Name, opts)
+			g.suffix = helperSuffix(output)
+			units = append(units, unit{p, typeSpecs, filepath.Join(p.Dir, output), g})
+			continue
+		}
+		if output == "" {
+			output = "{type}_gen_json.go"
+		}
+		for _, typeSpec := range typeSpecs {
+			g := newGenerator(p, typeSpec.Name.Name, opts)
+			path := filepath.Join(p.Dir, strings.ReplaceAll(output, "{type}", strings.ToLower(typeSpec.Name.Name)))
+			units = append(units, unit{p, []*ast.TypeSpec{typeSpec}, path, g})
+		}
+	}
+	var diags Diagnostics
+	for _, typeName := range cfg.Types {
+		if !found[typeName] {
+			diags = append(diags, Diagnostic{Message: fmt.Sprintf("type %v not found", typeName)})
+		}
+	}
+	switch {
+	case len(found) > 0 || len(diags) > 0:
+	case cfg.All:
+		diags = append(diags, Diagnostic{Message: "no exported struct types found"})
+	default:
+		diags = append(diags, Diagnostic{Message: fmt.Sprintf("no types marked with %s or %scodec found", marker, directivePrefixes[0])})
+	}
+	if len(diags) > 0 {
+		return nil, diags
+	}
+	return units, nil
+}
+
+// failure is a problem found while generating a type, which stops the
+// generation of the type.
+type failure struct {
+	pos token.Pos
+	msg string
+}
+
+// fail stops the generation of the type with a problem located at node, or
+// at the type if node is nil, which Generate returns.
+func fail(node ast.Node, format string, args ...any) {
+	pos := token.NoPos
+	if node != nil {
+		pos = node.Pos()
+	}
+	panic(failure{pos, fmt.Sprintf(format, args...)})
+}
+
+// catch calls fn, working on the type typeSpec, and returns the problem it
+// failed with, if any.
+func (g *generator) catch(typeSpec *ast.TypeSpec, fn func()) (d *Diagnostic) {
+	defer func() {
+		if r := recover(); r != nil {
+			f, ok := r.(failure)
+			if !ok {
+				panic(r)
+			}
+			if !f.pos.IsValid() {
+				f.pos = typeSpec.Pos()
+			}
+			d = &Diagnostic{Pos: g.fset.Position(f.pos), Message: typeSpec.Name.Name + ": " + f.msg}
+		}
+	}()
+	fn()
+	return nil
+}
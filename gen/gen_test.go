@@ -0,0 +1,518 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "testdata/unsupported", []string{"."}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pkgs[0]
+	g := newGenerator(p, "Unsupported", &Options{})
+	var problems []string
+	for _, d := range g.Validate("Unsupported", p.Types["Unsupported"].Type) {
+		problems = append(problems, strings.TrimPrefix(d.String(), p.Dir+string(os.PathSeparator)))
+	}
+	want := []string{
+		"unsupported.go:9:10: Unsupported.Chan: unsupported type chan int: channels cannot be represented in JSON (add `json:\"-\"` to ignore the field)",
+		"unsupported.go:10:10: Unsupported.Func: unsupported type func(): functions cannot be represented in JSON (add `json:\"-\"` to ignore the field)",
+		"unsupported.go:11:10: Unsupported.Complex: unsupported type complex128: JSON has no complex numbers (add `json:\"-\"` to ignore the field, or store the real and imaginary parts in float64 fields)",
+		"unsupported.go:12:10: Unsupported.Pointer: unsupported type unsafe.Pointer (add `json:\"-\"` to ignore the field)",
+		"unsupported.go:29:6: Unsupported.Nested[].Values{}.Baz: unsupported type complex64: JSON has no complex numbers (add `json:\"-\"` to ignore the field, or store the real and imaginary parts in float64 fields)",
+		"unsupported.go:16:17: Unsupported.Case: invalid case option \"upper\" (use case:ignore or case:strict)",
+		"unsupported.go:19:10: Unsupported.Bytes: unsupported type []byte: json/v2 encodes byte slices as base64 strings (add `json:\"-\"` to ignore the field, or use []int)",
+	}
+	if !slices.Equal(problems, want) {
+		t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(problems, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestJSONNames(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "testdata/names", []string{"."}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pkgs[0]
+	g := newGenerator(p, "Dup", &Options{})
+	diags := g.Validate("Dup", p.Types["Dup"].Type)
+	want := `Dup.B: JSON name "a" is already used by field A (rename one of them in its json tag)`
+	if len(diags) != 1 || diags[0].Severity != Error || diags[0].Pos.Line != 5 || diags[0].Message != want {
+		t.Errorf("got problems %v, want %q at line 5", diags, want)
+	}
+
+	// The name of Outer shadows the one of Inner
+	g = newGenerator(p, "Outer", &Options{})
+	if diags := g.Validate("Outer", p.Types["Outer"].Type); len(diags) != 0 {
+		t.Errorf("got problems %v, want none", diags)
+	}
+	var names []string
+	for _, m := range g.jsonMembers(p.Types["Outer"].Type.(*ast.StructType), false) {
+		names = append(names, m.name)
+	}
+	if want := []string{"age", "name"}; !slices.Equal(names, want) {
+		t.Errorf("got members %v, want %v", names, want)
+	}
+	files, err := Generate(t.Context(), Config{Dir: "testdata/names", Types: []string{"Outer"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range files {
+		if n := bytes.Count(b, []byte(`case "name":`)); n != 1 {
+			t.Errorf("got %d cases for name, want 1", n)
+		}
+		if bytes.Contains(b, []byte("p.Inner.Name")) {
+			t.Errorf("generated file encodes or decodes the shadowed Inner.Name")
+		}
+	}
+}
+
+func TestExportedStructs(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "", []string{"./testdata/all"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ExportedStructs(pkgs[0])
+	if want := []string{"Second", "First"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLoadPackages(t *testing.T) {
+	for _, c := range []struct {
+		tags string
+		want []string
+	}{
+		{"", []string{"Always"}},
+		{"special", []string{"Always", "Special"}},
+	} {
+		pkgs, err := LoadPackages(t.Context(), "", []string{"./testdata/tags"}, c.tags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := slices.Sorted(maps.Keys(pkgs[0].Types)); !slices.Equal(got, c.want) {
+			t.Errorf("tags %q: got types %v, want %v", c.tags, got, c.want)
+		}
+	}
+
+	_, err := LoadPackages(t.Context(), "", []string{"./testdata/broken"}, "")
+	if err == nil || !strings.Contains(err.Error(), "broken.go:4:") {
+		t.Errorf("got error %v, want a parse error in broken.go", err)
+	}
+}
+
+func TestTypeOptions(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "", []string{"./testdata/markers"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pkgs[0]
+	if got, want := MarkedTypes(p), []string{"Plain", "Strict", "NoCollect", "Unknown", "Invalid", "Spaced"}; !slices.Equal(got, want) {
+		t.Errorf("got marked types %v, want %v", got, want)
+	}
+	g := newGenerator(p, "Plain", &Options{Collect: true})
+	for _, c := range []struct {
+		typeName string
+		want     typeOptions
+		problem  string
+	}{
+		{"Plain", typeOptions{collect: true}, ""},
+		{"Strict", typeOptions{rejectUnknown: true, rejectDuplicates: true, deterministic: true, collect: true}, ""},
+		{"NoCollect", typeOptions{}, ""},
+		{"Unknown", typeOptions{collect: true}, `markers.go:18:6: Unknown: unknown marker option "frobnicate" (use strict, deterministic, marshal-only or collect)`},
+		{"Invalid", typeOptions{collect: true}, `markers.go:21:6: Invalid: invalid value "maybe" of marker option strict (use true or false)`},
+		{"Spaced", typeOptions{deterministic: true, collect: true}, ""},
+	} {
+		got, d := g.typeOptions(p.Types[c.typeName])
+		var problem string
+		if d != nil {
+			problem = d.String()
+		}
+		if got != c.want || !strings.HasSuffix(problem, c.problem) || (problem == "") != (c.problem == "") {
+			t.Errorf("%s: got %+v, %q, want %+v, %q", c.typeName, got, problem, c.want, c.problem)
+		}
+	}
+}
+
+func TestHasDirective(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "", []string{"./testdata/markers"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(pkgs[0], "Directed", &Options{})
+	for _, c := range []struct {
+		typeName, directive string
+		want                bool
+	}{
+		{"Directed", "rejectunknown", true},
+		{"Directed", "rejectduplicates", true},
+		{"Directed", "reject", false},
+		{"Strict", "rejectunknown", false},
+	} {
+		if got := g.hasDirective(c.typeName, c.directive); got != c.want {
+			t.Errorf("%s: hasDirective(%q) = %v, want %v", c.typeName, c.directive, got, c.want)
+		}
+	}
+}
+
+func TestHelperSuffix(t *testing.T) {
+	for _, c := range []struct{ fileName, want string }{
+		{"examples_gen_json.go", "ExamplesGenJSON"},
+		{"dir/models.go", "Models"},
+		{"api-v2.json.go", "ApiV2JSON"},
+	} {
+		if got := helperSuffix(c.fileName); got != c.want {
+			t.Errorf("helperSuffix(%q) = %q, want %q", c.fileName, got, c.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	files, err := Generate(t.Context(), Config{Patterns: []string{"./testdata/all"}, All: true, Output: "all_gen_json.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Abs("testdata/all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "all_gen_json.go")
+	if got := slices.Collect(maps.Keys(files)); !slices.Equal(got, []string{path}) {
+		t.Fatalf("got files %v, want %v", got, path)
+	}
+	for _, want := range []string{"func (p *First) UnmarshalJSONFrom(", "func (p *Second) MarshalJSONTo("} {
+		if !bytes.Contains(files[path], []byte(want)) {
+			t.Errorf("generated file lacks %q", want)
+		}
+	}
+
+	_, err = Generate(t.Context(), Config{Dir: "testdata/unsupported", Types: []string{"Unsupported", "Missing"}})
+	var diags Diagnostics
+	if !errors.As(err, &diags) || len(diags) != 1 || diags[0].Message != "type Missing not found" {
+		t.Errorf("got error %v, want type Missing not found", err)
+	}
+	_, err = Generate(t.Context(), Config{Dir: "testdata/nostructs", All: true})
+	if !errors.As(err, &diags) || len(diags) != 1 || diags[0].Message != "no exported struct types found" {
+		t.Errorf("got error %v, want no exported struct types found", err)
+	}
+	_, err = Generate(t.Context(), Config{Dir: "testdata/nostructs"})
+	if !errors.As(err, &diags) || len(diags) != 1 || !strings.HasPrefix(diags[0].Message, "no types marked") {
+		t.Errorf("got error %v, want no types marked", err)
+	}
+	_, err = Generate(t.Context(), Config{Dir: "testdata/unsupported", Types: []string{"Unsupported"}})
+	if !errors.As(err, &diags) || len(diags) != 7 || diags[0].Pos.Line != 9 || diags[0].Pos.Column != 10 {
+		t.Errorf("got error %v, want the problems of Unsupported", err)
+	}
+}
+
+func TestImportSet(t *testing.T) {
+	s := newImportSet()
+	for _, c := range []struct{ path, want string }{
+		{"encoding/json/v2", "json"},
+		{"encoding/json", "jsonv1"},
+		{"example.com/json", "json2"},
+		{"example.org/json/v3", "json3"},
+		{"gopkg.in/yaml.v3", "yaml"},
+		{"github.com/mattn/go-sqlite3", "sqlite3"},
+		{"example.com/go", "go2"},
+		{"math/big", "big"},
+		{"encoding/json", "jsonv1"},
+	} {
+		if got := s.use(c.path); got != c.want {
+			t.Errorf("use(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+	used := map[string]bool{"json": true, "jsonv1": true, "json2": true, "yaml": true, "big": true}
+	want := []string{
+		`jsonv1 "encoding/json"`,
+		`"encoding/json/v2"`,
+		`"math/big"`,
+		``,
+		`json2 "example.com/json"`,
+		`yaml "gopkg.in/yaml.v3"`,
+	}
+	if got := s.specs(used); !slices.Equal(got, want) {
+		t.Errorf("got specs:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGenerateImports(t *testing.T) {
+	files, err := Generate(t.Context(), Config{Dir: "testdata/imports", Types: []string{"Imports"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, imp := range f.Imports {
+			spec := imp.Path.Value
+			if imp.Name != nil {
+				spec = imp.Name.Name + " " + spec
+			}
+			got = append(got, spec)
+		}
+		want := []string{
+			`"bytes"`,
+			`jsonv1 "encoding/json"`,
+			`"encoding/json/jsontext"`,
+			`"encoding/json/v2"`,
+			`"errors"`,
+			`"fmt"`,
+			`"maps"`,
+			`"math/big"`,
+			`"reflect"`,
+			`"slices"`,
+			`"strconv"`,
+			`"strings"`,
+			`"time"`,
+			`"unicode"`,
+			`"unicode/utf8"`,
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got imports:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+// TestGofmt checks that the generated files are formatted like gofmt.
+func TestGofmt(t *testing.T) {
+	files, err := filepath.Glob("../examples/*_gen_json*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := Generate(t.Context(), Config{Dir: "testdata/imports", Types: []string{"Imports"}, Options: Options{Collect: true, Limits: true, Fallback: true}})
+	// The fallback files lack limits and collecting errors
+	var diags Diagnostics
+	if err != nil && (!errors.As(err, &diags) || diags.HasErrors()) {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for path, src := range generated {
+		path = filepath.Join(dir, filepath.Base(path))
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	out, err := exec.Command("gofmt", append([]string{"-l"}, files...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("gofmt: %v\n%s", err, out)
+	}
+	if len(out) > 0 {
+		t.Errorf("files not formatted like gofmt:\n%s", out)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(want ...string) {
+		t.Helper()
+		diffs, err := Check(t.Context(), Config{Dir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, d := range diffs {
+			got = append(got, filepath.Base(d.Path)+" "+d.Reason)
+			if d.Diff == "" {
+				t.Errorf("%s: no diff", d.Path)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	write("go.mod", "module check\n\ngo 1.27\n")
+	write("types.go", "package check\n\n//json:generate\ntype A struct{ X int }\n\n//json:generate\ntype B struct{ Y int }\n")
+	check("a_gen_json.go missing", "b_gen_json.go missing")
+
+	files, err := Generate(t.Context(), Config{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		write(filepath.Base(path), string(content))
+	}
+	check()
+
+	write("types.go", "package check\n\n//json:generate\ntype A struct{ X string }\n")
+	check("a_gen_json.go stale", "b_gen_json.go orphaned")
+}
+
+func TestShape(t *testing.T) {
+	pkgs, err := LoadPackages(t.Context(), "", []string{"./testdata/imports"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(pkgs[0], "Imports", &Options{})
+	var b strings.Builder
+	g.shape(&b, &ast.Ident{Name: "Imports"})
+	want := `Imports=struct{Number json.Number "json:\"number\""; At time.Time "json:\"at\""; Big *big.Int "json:\"big\""; Times []time.Time "json:\"times\""; Index map[string]bool "json:\"index\""}`
+	if got := b.String(); got != want {
+		t.Errorf("got shape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	files, err := Generate(t.Context(), Config{Dir: "testdata/diagnostics", Types: []string{"Warned"}})
+	var diags Diagnostics
+	if !errors.As(err, &diags) || diags.HasErrors() || len(files) != 1 {
+		t.Fatalf("got %d files and error %v, want a file and a warning", len(files), err)
+	}
+	if got, want := diags[0].String(), `diagnostics.go:4:12: warning: Warned.Count: unknown json tag option "nocase" is ignored`; !strings.HasSuffix(got, want) {
+		t.Errorf("got diagnostic %s, want %s", got, want)
+	}
+
+	// The problems of all the types are reported
+	files, err = Generate(t.Context(), Config{Dir: "testdata/diagnostics", Types: []string{"Warned", "Malformed", "Keyed", "Quoted"}})
+	if !errors.As(err, &diags) || files != nil {
+		t.Fatalf("got %d files and error %v, want diagnostics only", len(files), err)
+	}
+	var got []string
+	for _, d := range diags {
+		got = append(got, fmt.Sprintf("%d:%d %s", d.Pos.Line, d.Pos.Column, d.Severity))
+	}
+	if want := []string{"4:12 warning", "8:14 error", "12:12 error", "16:12 error"}; !slices.Equal(got, want) {
+		t.Errorf("got diagnostics %v, want %v", got, want)
+	}
+	if got, want := diags[len(diags)-1].Message, "Quoted.Count: json tag option \"string\" is not supported: numbers are not quoted (remove the option, or add `json:\"-\"` to ignore the field)"; got != want {
+		t.Errorf("got diagnostic %s, want %s", got, want)
+	}
+
+	// Problems found while generating are located at their node, or the type
+	pkgs, err := LoadPackages(t.Context(), "testdata/diagnostics", []string{"."}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeSpec := pkgs[0].Types["Keyed"]
+	g := newGenerator(pkgs[0], "Keyed", &Options{})
+	d := g.catch(typeSpec, func() { fail(nil, "oops") })
+	if d == nil || d.Pos.Line != 11 || d.Message != "Keyed: oops" || d.Severity != Error {
+		t.Errorf("got %v, want Keyed: oops at line 11", d)
+	}
+	if d := g.catch(typeSpec, func() {}); d != nil {
+		t.Errorf("got %v, want no problem", d)
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	table, _, err := parseTOML([]byte(`# Settings
+strict = true
+max-depth = 1_000
+time-format = 'DateOnly' # literal
+tags = ["a", "b"]
+
+[codecs]
+"example.com/ext.ID" = "text"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprint(table)
+	want := `map[codecs:map[example.com/ext.ID:text] max-depth:1000 strict:true tags:[a b] time-format:DateOnly]`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	for _, c := range []struct {
+		data string
+		line int
+	}{
+		{"a = 1\na = 2\n", 2},
+		{"a = \"b\n", 1},
+		{"\n\na = 1.5\n", 3},
+		{"[codecs\n", 1},
+		{"a\n", 1},
+	} {
+		if _, line, err := parseTOML([]byte(c.data)); err == nil || line != c.line {
+			t.Errorf("parseTOML(%q): got error %v at line %d, want an error at line %d", c.data, err, line, c.line)
+		}
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module config\n\ngo 1.27\n")
+	write("gen-json.toml", "strict = true\nnil-slice = \"null\"\noutput = \"{type}_json.go\"\ntags = [\"extra\"]\n\n[codecs]\n\"net/netip.Addr\" = \"text\"\n")
+	write("models/models.go", "package models\n\nimport \"net/netip\"\n\n//json:generate\ntype A struct {\n\tAddr netip.Addr\n\tList []int\n}\n")
+	write("models/extra.go", "//go:build extra\n\npackage models\n\n//json:generate\ntype B struct{ X int }\n")
+	generate := func(cfg Config) map[string][]byte {
+		t.Helper()
+		files, err := Generate(t.Context(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	files := generate(Config{Dir: filepath.Join(dir, "models")})
+	a, b := filepath.Join(dir, "models", "a_json.go"), filepath.Join(dir, "models", "b_json.go")
+	if got := slices.Sorted(maps.Keys(files)); !slices.Equal(got, []string{a, b}) {
+		t.Fatalf("got files %v, want %v", got, []string{a, b})
+	}
+	nullSlice := "if (*p).List == nil {\n\t\t\tif err = e.WriteToken(jsontext.Null)"
+	for _, want := range []string{"UnmarshalText(", nullSlice} {
+		if !bytes.Contains(files[a], []byte(want)) {
+			t.Errorf("generated file lacks %q", want)
+		}
+	}
+	if bytes.Contains(files[a], []byte("json.RejectUnknownMembers")) {
+		t.Error("generated file reads json.RejectUnknownMembers, despite strict")
+	}
+
+	// Options set like flags override the file
+	files = generate(Config{Dir: dir, Patterns: []string{"./models"}, Types: []string{"A"}, Override: func(o *Options) { o.RejectUnknown = false }})
+	if !bytes.Contains(files[a], []byte("json.RejectUnknownMembers")) {
+		t.Error("Override does not override strict")
+	}
+
+	// The file of the package replaces the file of the module
+	write("models/gen-json.json", `{"nil-slice": "empty", "codecs": {"net/netip.Addr": "json"}}`)
+	files = generate(Config{Dir: dir, Patterns: []string{"./models"}, Types: []string{"A"}})
+	path := filepath.Join(dir, "models", "a_gen_json.go")
+	if bytes.Contains(files[path], []byte("jsontext.Null")) || !bytes.Contains(files[path], []byte("json.UnmarshalDecode(")) {
+		t.Error("the settings of the package file are not applied")
+	}
+
+	write("models/gen-json.json", `{"nil-slice": "never"}`)
+	_, err := Generate(t.Context(), Config{Dir: dir, Patterns: []string{"./models"}, Types: []string{"A"}})
+	if err == nil || !strings.Contains(err.Error(), `invalid nil slice format "never"`) {
+		t.Errorf("got error %v, want an invalid nil slice format", err)
+	}
+	write("models/gen-json.json", "{\n  \"strict\": 1\n}")
+	_, err = Generate(t.Context(), Config{Dir: dir, Patterns: []string{"./models"}, Types: []string{"A"}})
+	var diags Diagnostics
+	if !errors.As(err, &diags) || diags[0].Pos.Line != 2 || filepath.Base(diags[0].Pos.Filename) != "gen-json.json" {
+		t.Errorf("got error %v, want a problem at gen-json.json:2", err)
+	}
+}
@@ -0,0 +1,1243 @@
+package gen
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"log"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExportedStructs returns the names of the exported struct types of package
+// p, in the order they are declared, leaving out generated files.
+func ExportedStructs(p *Package) []string {
+	var names []string
+	for name, ts := range p.Types {
+		if _, ok := ts.Type.(*ast.StructType); ok && ast.IsExported(name) && !ast.IsGenerated(p.Files[name]) {
+			names = append(names, name)
+		}
+	}
+	sortByPosition(p, names)
+	return names
+}
+
+// sortByPosition sorts the names of types of package p in the order they are
+// declared.
+func sortByPosition(p *Package, names []string) {
+	slices.SortFunc(names, func(a, b string) int {
+		pa, pb := p.Fset.Position(p.Types[a].Pos()), p.Fset.Position(p.Types[b].Pos())
+		return cmp.Or(cmp.Compare(pa.Filename, pb.Filename), cmp.Compare(pa.Offset, pb.Offset))
+	})
+}
+
+// validate returns the problems of the types typeSpecs.
+func (g *generator) validate(typeSpecs []*ast.TypeSpec) Diagnostics {
+	var diags Diagnostics
+	for _, typeSpec := range typeSpecs {
+		restore := g.inFile(typeSpec.Name.Name)
+		if _, problem := g.typeOptions(typeSpec); problem != nil {
+			diags = append(diags, *problem)
+		}
+		if problem := g.catch(typeSpec, func() {
+			diags = append(diags, g.Validate(typeSpec.Name.Name, typeSpec.Type)...)
+		}); problem != nil {
+			diags = append(diags, *problem)
+		}
+		restore()
+	}
+	return diags
+}
+
+// generate writes the methods of the type typeSpec, with the options of its
+// marker.
+func (g *generator) generate(typeSpec *ast.TypeSpec) {
+	g.opts, _ = g.typeOptions(typeSpec)
+	if !g.opts.marshalOnly {
+		g.GenerateUnmarshalJSON(typeSpec.Name.Name, typeSpec.Type)
+		if g.opts.collect {
+			g.GenerateUnmarshalJSONCollect(typeSpec.Name.Name, typeSpec.Type)
+		}
+	}
+	g.GenerateMarshalJSON(typeSpec.Name.Name, typeSpec.Type)
+	if g.cfg.Fingerprint {
+		g.GenerateFingerprint(typeSpec.Name.Name)
+	}
+}
+
+// helperSuffix returns the suffix of the helpers in file fileName, from its
+// base name in camel case (e.g. "ExamplesGenJSON" for "examples_gen_json.go").
+func helperSuffix(fileName string) string {
+	base := strings.TrimSuffix(filepath.Base(fileName), ".go")
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.EqualFold(word, "json") {
+			word = "JSON"
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+type generator struct {
+	fset      *token.FileSet           // file set of parsed package files
+	name      string                   // package name
+	typeName  string                   // name of the type methods are generated for
+	suffix    string                   // suffix of helper names, unique in the package
+	build     string                   // build constraint of the generated file, if any
+	cfg       *Options                 // options of every type
+	imports   *importSet               // imports of the generated file
+	body      bytes.Buffer             // generated function bodies
+	helpers   map[string]string        // map of helper function names to code
+	files     map[string]*ast.File     // map of package types to declaring files
+	file      *ast.File                // file declaring the type being generated
+	types     map[string]*ast.TypeSpec // map of package types
+	opts      typeOptions              // options of the type being generated
+	fallbacks map[string]bool          // set of the types whose fallback methods are generated
+	options   map[string]bool          // set of options read by the current method
+	collect   bool                     // whether the current method collects errors
+	lvl       int                      // indent level
+	depth     int                      // nesting level of arrays and maps
+}
+
+// newGenerator returns a generator of the methods of the type typeName of
+// package p, with the options opts.
+func newGenerator(p *Package, typeName string, opts *Options) *generator {
+	return &generator{
+		fset:     p.Fset,
+		name:     p.Name,
+		typeName: typeName,
+		suffix:   typeName,
+		cfg:      opts,
+		imports:  newImportSet(),
+		helpers:  make(map[string]string),
+		files:    p.Files,
+		file:     p.Files[typeName],
+		types:    p.Types,
+		opts:     opts.typeOptions(),
+		options:  make(map[string]bool),
+	}
+}
+
+func (g *generator) GenerateUnmarshalJSON(typeName string, typeExpr ast.Expr) {
+	if g.cfg.Debug {
+		g.useImports("log")
+	}
+	g.useImports("encoding/json/jsontext", "encoding/json/v2")
+	// UnmarshalJSON goes through json.Unmarshal, so that errors are annotated
+	// and trailing data is rejected the same way as when UnmarshalJSONFrom is
+	// called by json/v2. Holding the input, it also locates errors by line
+	// and column.
+	if g.cfg.limited() {
+		g.unmarshalerLimited(typeName)
+	} else {
+		g.writeMultiline(fmt.Sprintf(`
+			func (p *%[1]s) UnmarshalJSON(b []byte) error {
+				return %[2]s(b, json.Unmarshal(b, p))
+			}
+
+			func (p *%[1]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+		`, typeName, g.usePositionError()))
+	}
+	g.indent()
+	g.writeMultiline(`
+		var (
+			t   jsontext.Token
+			err error
+		)
+	`)
+	code := g.capture(func() {
+		g.unmarshalerValue(ast.NewIdent(typeName), "(*p)", func() { g.unmarshaler(typeName, typeExpr, "(*p)", typeName) })
+	})
+	g.declareOptions("d")
+	g.unmarshalerBaseDepth(code)
+	g.body.WriteString(code)
+	g.writeLine("return nil")
+	g.unindent()
+	g.writeLine("}")
+}
+
+func (g *generator) writeMultiline(s string) {
+	lines := strings.Split(s, "\n")
+	if lines[0] == "" {
+		lines = lines[1:]
+	}
+	if strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	minIndent := math.MaxInt
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, "\t"))
+		minIndent = min(indent, minIndent)
+	}
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = line[minIndent:]
+		}
+	}
+	for _, line := range lines {
+		g.writeLine(line)
+	}
+}
+
+func (g *generator) writeLine(line string) {
+	ident := strings.Repeat("\t", g.lvl)
+	output := ident + line + "\n"
+	g.body.Write([]byte(output))
+}
+
+func (g *generator) indent() {
+	g.lvl++
+}
+
+func (g *generator) unindent() {
+	g.lvl--
+}
+
+// capture returns the code written by gen, instead of writing it.
+func (g *generator) capture(gen func()) string {
+	start := g.body.Len()
+	gen()
+	code := g.body.String()[start:]
+	g.body.Truncate(start)
+	return code
+}
+
+// indentCode indents the lines of code, as captured, by one more level.
+func indentCode(code string) string {
+	lines := strings.SplitAfter(code, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "\t" + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// tmpName returns the name of a temporary variable for the current nesting
+// level, so that nested arrays and maps do not shadow each other's variables.
+func (g *generator) tmpName(prefix string) string {
+	if g.depth == 0 {
+		return prefix
+	}
+	return fmt.Sprintf("%s%d", prefix, g.depth)
+}
+
+func (g *generator) useImports(imports ...string) {
+	for _, imp := range imports {
+		g.imports.use(imp)
+	}
+}
+
+// useTypeImports imports every package referenced by type expression expr.
+func (g *generator) useTypeImports(expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			g.useImports(g.importPath(x))
+		}
+		return false
+	})
+}
+
+// useHelper emits the helper function name once, using gen to write its code.
+// Helpers are named after the generated type, or the file generated for
+// several types, so that other generated files in the same package do not
+// redeclare them.
+func (g *generator) useHelper(name string, gen func(h *generator, name string)) string {
+	name += g.suffix
+	if _, ok := g.helpers[name]; ok {
+		return name
+	}
+	g.helpers[name] = ""
+	h := &generator{
+		fset:     g.fset,
+		name:     g.name,
+		typeName: g.typeName,
+		suffix:   g.suffix,
+		cfg:      g.cfg,
+		imports:  g.imports,
+		helpers:  g.helpers,
+		files:    g.files,
+		file:     g.file,
+		types:    g.types,
+	}
+	gen(h, name)
+	g.helpers[name] = h.body.String()
+	return name
+}
+
+// importPath resolves a package name used in the file declaring the current
+// type to its import path.
+func (g *generator) importPath(x *ast.Ident) string {
+	path, ok := g.lookupImport(x.Name)
+	if !ok {
+		fail(x, "unresolved package %s", x.Name)
+	}
+	return path
+}
+
+// lookupImport is like importPath, but reports whether name was resolved
+// instead of failing.
+func (g *generator) lookupImport(name string) (string, bool) {
+	for _, imp := range g.file.Imports {
+		// The parser only accepts valid paths
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name == name {
+			return path, true
+		}
+		if imp.Name == nil && packageName(path) == name {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// inFile switches the file used to resolve package names to the one declaring
+// typeName, returning a function that switches it back.
+func (g *generator) inFile(typeName string) func() {
+	file := g.file
+	g.file = g.files[typeName]
+	return func() { g.file = file }
+}
+
+// source returns the generated file, formatted like gofmt.
+func (g *generator) source() ([]byte, error) {
+	var code bytes.Buffer
+	g.body.WriteTo(&code)
+	for _, name := range slices.Sorted(maps.Keys(g.helpers)) {
+		fmt.Fprintf(&code, "\n%s", g.helpers[name])
+	}
+	// Import the packages the code uses, whatever was imported while
+	// generating it
+	used, err := qualifiers(append([]byte("package "+g.name+"\n"), code.Bytes()...))
+	if err != nil {
+		return nil, fmt.Errorf("parse generated code: %v", err)
+	}
+	f := new(bytes.Buffer)
+	fmt.Fprintf(f, "// Code generated by go-gen-json. DO NOT EDIT.\n")
+	if g.build != "" {
+		fmt.Fprintf(f, "\n//go:build %s\n\n", g.build)
+	}
+	fmt.Fprintf(f, "package %s\n\n", g.name)
+	if specs := g.imports.specs(used); len(specs) > 0 {
+		fmt.Fprintf(f, "import (\n%s\n)\n\n", strings.Join(specs, "\n"))
+	}
+	code.WriteTo(f)
+	src, err := format.Source(f.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v", err)
+	}
+	return src, nil
+}
+
+func (g *generator) unmarshaler(typeName string, typeExpr ast.Expr, varExpr string, originalName string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler: %s", typeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler: %s")`, typeName))
+	}
+	switch ts := typeExpr.(type) {
+	case *ast.Ident:
+		g.unmarshalerIdent(ts.Name, varExpr, typeName)
+	case *ast.SelectorExpr:
+		g.unmarshalerSelector(typeName, ts, varExpr)
+	case *ast.StructType:
+		g.unmarshalerStruct(typeName, ts, varExpr)
+	case *ast.ArrayType:
+		g.unmarshalerArray(ts.Elt, varExpr)
+	case *ast.MapType:
+		g.unmarshalerMap(typeName, ts.Key, ts.Value, varExpr)
+	case *ast.StarExpr:
+		g.unmarshalerPointer(typeName, ts, varExpr, originalName)
+	default:
+		fail(typeExpr, "not implemented for type: %T", ts)
+	}
+}
+
+func (g *generator) unmarshalerIdent(typeName string, varExpr string, targetTypeName string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler ident: %s [%s]", typeName, targetTypeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler ident: %s [%s]")`, typeName, targetTypeName))
+	}
+	typeName = predeclaredShape(typeName)
+	switch typeName {
+	case "string":
+		g.writeMultiline(fmt.Sprintf(`
+			t, err = d.ReadToken()
+			if err != nil {
+				return err
+			}
+			if t.Kind() != '"' {
+				return %s
+			}
+		`, g.unmarshalError(varExpr, "nil")))
+		g.unmarshalerString("t.String()", varExpr)
+		g.writeLine(fmt.Sprintf("%s = %s(t.String())", varExpr, targetTypeName))
+	case "int", "int8", "int16", "int32", "int64":
+		g.useImports("strconv")
+		g.unmarshalerNumberToken(varExpr)
+		g.writeMultiline(fmt.Sprintf(`
+			if n, err := strconv.ParseInt(t.String(), 10, %d); err != nil {
+				return %s
+			} else {
+				%s = %s(n)
+			}
+		`, bitSize(typeName), g.unmarshalError(varExpr, "err"), varExpr, targetTypeName))
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		g.useImports("strconv")
+		g.unmarshalerNumberToken(varExpr)
+		g.writeMultiline(fmt.Sprintf(`
+			if n, err := strconv.ParseUint(t.String(), 10, %d); err != nil {
+				return %s
+			} else {
+				%s = %s(n)
+			}
+		`, bitSize(typeName), g.unmarshalError(varExpr, "err"), varExpr, targetTypeName))
+	case "bool":
+		g.writeMultiline(fmt.Sprintf(`
+			t, err = d.ReadToken()
+			if err != nil {
+				return err
+			}
+			if t.Kind() != 't' && t.Kind() != 'f' {
+				return %s
+			}
+			%s = t.Kind() == 't'`, g.unmarshalError(varExpr, "nil"), varExpr))
+	case "float32", "float64":
+		g.useImports("strconv")
+		g.unmarshalerNumberToken(varExpr)
+		g.writeMultiline(fmt.Sprintf(`
+			if n, err := strconv.ParseFloat(t.String(), %d); err != nil {
+				return %s
+			} else {
+				%s = %s(n)
+			}
+		`, bitSize(typeName), g.unmarshalError(varExpr, "err"), varExpr, targetTypeName))
+	case "any":
+		g.writeMultiline(fmt.Sprintf(`
+			if %[1]s, err = %[2]s(d, %[1]s%[3]s); err != nil {
+				return err
+			}
+		`, varExpr, g.useUnmarshalAny(), g.limitsArg()))
+	default:
+		if typeSpec, ok := g.types[typeName]; ok {
+			defer g.inFile(typeName)()
+			g.unmarshaler(typeSpec.Name.Name, typeSpec.Type, varExpr, targetTypeName)
+		} else {
+			fail(nil, "unrecognized type: %s", typeName)
+		}
+	}
+}
+
+func (g *generator) unmarshalerSelector(typeName string, expr *ast.SelectorExpr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler selector: %s (%s)", typeName, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler selector: %s (%s)")`, typeName, varExpr))
+	}
+	X, ok := expr.X.(*ast.Ident)
+	if !ok {
+		fail(expr, "go-gen-json does not support non-Time selector expr")
+	}
+	switch path := g.importPath(X) + "." + expr.Sel.Name; {
+	case g.cfg.Codecs[path] == "json":
+		g.writeMultiline(fmt.Sprintf(`
+			if err = json.UnmarshalDecode(d, %s); err != nil {
+				return err
+			}
+		`, g.addrAsType(expr, typeName, varExpr)))
+	case g.cfg.Codecs[path] == "text" || path == "time.Time" && g.cfg.TimeFormat == "":
+		g.writeMultiline(fmt.Sprintf(`
+			t, err = d.ReadToken()
+			if err != nil {
+				return err
+			}
+			if t.Kind() != '"' {
+				return %s
+			}
+			if err = %s.UnmarshalText([]byte(t.String())); err != nil {
+				return %s
+			}
+		`, g.unmarshalError(varExpr, "nil"), g.asType(expr, typeName, varExpr), g.unmarshalError(varExpr, "err")))
+	case path == "time.Time":
+		g.writeMultiline(fmt.Sprintf(`
+			t, err = d.ReadToken()
+			if err != nil {
+				return err
+			}
+			if t.Kind() != '"' {
+				return %s
+			}
+			if tm, err := %s.Parse(%s, t.String()); err != nil {
+				return %s
+			} else {
+				%s = %s(tm)
+			}
+		`, g.unmarshalError(varExpr, "nil"), g.imports.use("time"), g.timeLayout(), g.unmarshalError(varExpr, "err"), varExpr, typeName))
+	case path == "encoding/json.Number":
+		g.useTypeImports(expr)
+		g.unmarshalerNumberString(varExpr, typeName)
+	case path == "math/big.Int", path == "math/big.Float", path == "math/big.Rat":
+		g.unmarshalerBig(expr.Sel.Name, varExpr, g.asType(expr, typeName, varExpr))
+	default:
+		fail(expr, "go-gen-json does not support external packages")
+	}
+}
+
+// unmarshalerNumberToken reads the token of a Go number into t: a JSON
+// number or, with json.StringifyNumbers, a JSON string holding one.
+func (g *generator) unmarshalerNumberToken(varExpr string) {
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		if t.Kind() != '0' || %[1]s {
+			if !%[1]s || t.Kind() != '"' {
+				return %[3]s
+			}
+			if !%[2]s(t.String()) {
+				return %[4]s
+			}
+		}
+	`, g.useOption("json.StringifyNumbers"), g.useIsNumber(), g.unmarshalError(varExpr, "nil"), g.unmarshalError(varExpr, "strconv.ErrSyntax")))
+}
+
+// asType returns varExpr as a value of the external type expr. A named type
+// declared as e.g. type Stamp time.Time does not have the methods of
+// time.Time, so varExpr of type typeName is converted through a pointer.
+func (g *generator) asType(expr *ast.SelectorExpr, typeName string, varExpr string) string {
+	if typeName == g.typeString(expr) {
+		return varExpr
+	}
+	g.useTypeImports(expr)
+	return fmt.Sprintf("(*%s)(&%s)", g.typeString(expr), varExpr)
+}
+
+// addrAsType returns the address of varExpr as a pointer to the external type
+// expr, like asType.
+func (g *generator) addrAsType(expr *ast.SelectorExpr, typeName string, varExpr string) string {
+	if v := g.asType(expr, typeName, varExpr); v != varExpr {
+		return v
+	}
+	return "&" + varExpr
+}
+
+// timeLayout returns the expression of the layout of TimeFormat: a constant
+// of package time, or a string literal.
+func (g *generator) timeLayout() string {
+	switch g.cfg.TimeFormat {
+	case "ANSIC", "UnixDate", "RubyDate", "RFC822", "RFC822Z", "RFC850",
+		"RFC1123", "RFC1123Z", "RFC3339", "RFC3339Nano", "Kitchen",
+		"Stamp", "StampMilli", "StampMicro", "StampNano",
+		"DateTime", "DateOnly", "TimeOnly":
+		return g.imports.use("time") + "." + g.cfg.TimeFormat
+	}
+	return strconv.Quote(g.cfg.TimeFormat)
+}
+
+// unmarshalerNumberString decodes the exact text of a JSON number into a
+// string type, such as json.Number or a string with the format:number option.
+// Like encoding/json, a JSON string holding a valid number is also accepted.
+func (g *generator) unmarshalerNumberString(varExpr string, targetTypeName string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler number string: %s [%s]", varExpr, targetTypeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler number string: %s [%s]")`, varExpr, targetTypeName))
+	}
+	g.useImports("errors", "strconv")
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		switch t.Kind() {
+		case '0':
+			%[1]s = %[2]s(t.String())
+		case '"':
+			s := t.String()
+			if !%[5]s(s) {
+				return %[3]s
+			}
+			%[1]s = %[2]s(s)
+		default:
+			return %[4]s
+		}
+	`, varExpr, targetTypeName,
+		g.unmarshalError(varExpr, `errors.New("invalid number literal: " + strconv.Quote(s))`),
+		g.unmarshalError(varExpr, "nil"),
+		g.useIsNumber()))
+}
+
+// unmarshalerBig decodes the exact text of a JSON number into a big.Int,
+// big.Float or big.Rat, without rounding through float64. valueExpr is varExpr
+// converted to the big type.
+func (g *generator) unmarshalerBig(kind string, varExpr string, valueExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler big: %s (%s)", kind, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler big: %s (%s)")`, kind, varExpr))
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		if t.Kind() != '0' {
+			return %s
+		}
+	`, g.unmarshalError(varExpr, "nil")))
+	switch kind {
+	case "Int":
+		g.useImports("strconv")
+		g.writeMultiline(fmt.Sprintf(`
+			if _, ok := %s.SetString(t.String(), 10); !ok {
+				return %s
+			}
+		`, valueExpr, g.unmarshalError(varExpr, "strconv.ErrSyntax")))
+	case "Float":
+		// Four bits per digit are enough to keep every decimal digit
+		g.writeMultiline(fmt.Sprintf(`
+			if _, _, err := %s.SetPrec(max(64, 4*uint(len(t.String())))).Parse(t.String(), 10); err != nil {
+				return %s
+			}
+		`, valueExpr, g.unmarshalError(varExpr, "err")))
+	case "Rat":
+		g.useImports("strconv")
+		g.writeMultiline(fmt.Sprintf(`
+			if _, ok := %s.SetString(t.String()); !ok {
+				return %s
+			}
+		`, valueExpr, g.unmarshalError(varExpr, "strconv.ErrSyntax")))
+	}
+}
+
+func (g *generator) unmarshalerStruct(typeName string, ts *ast.StructType, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler struct: %s", typeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler struct: %s")`, typeName))
+	}
+	members := g.jsonMembers(ts, g.hasDirective(typeName, "required"))
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		if t.Kind() != '{' {
+			return %s
+		}
+	`, g.unmarshalError(varExpr, "nil")))
+	g.unmarshalerDepth(varExpr)
+	if g.cfg.limited() {
+		g.writeLine("members := 0")
+	}
+	// Folding names may match the same member twice, which the Decoder cannot
+	// detect as a duplicate name
+	folding := g.capture(func() { g.unmarshalerFoldName(members) })
+	forceDuplicates := g.opts.rejectDuplicates || g.hasDirective(typeName, "rejectduplicates")
+	var p *presence
+	if (forceDuplicates || folding != "") && len(members) > 0 || slices.ContainsFunc(members, func(m jsonMember) bool { return m.required }) {
+		p = newPresence(members)
+		p.duplicates = forceDuplicates || folding != ""
+		p.forced = forceDuplicates
+		g.writeLine(p.decl())
+	}
+	if forceDuplicates && !g.opts.rejectUnknown && !g.hasDirective(typeName, "rejectunknown") {
+		g.writeLine("var skipped map[string]struct{}")
+	}
+	g.writeLine("for d.PeekKind() != '}' {")
+	g.indent()
+	if g.cfg.limited() {
+		g.unmarshalerCount("members", "members", varExpr)
+		g.writeLine("members++")
+	}
+	g.writeMultiline(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		name := t.String()
+	`)
+	g.unmarshalerString("name", varExpr)
+	if folding != "" {
+		g.writeMultiline(folding)
+	}
+	g.writeLine("switch name {")
+	visible := g.dominantFields(ts)
+	for _, field := range ts.Fields.List {
+		g.unmarshalerField(field, varExpr, p, visible)
+	}
+	g.writeLine("default:")
+	g.indent()
+	unknown := g.unmarshalError(varExpr, "json.ErrUnknownName")
+	if g.opts.rejectUnknown || g.hasDirective(typeName, "rejectunknown") {
+		g.writeLine("return " + unknown)
+	} else {
+		g.writeMultiline(fmt.Sprintf(`
+			if %s {
+				return %s
+			}
+		`, g.lazyOption("d", "json.RejectUnknownMembers"), unknown))
+		if forceDuplicates {
+			// Skipped members may be duplicated as well
+			g.writeMultiline(fmt.Sprintf(`
+				if _, ok := skipped[name]; ok {
+					return %s(d, t)
+				}
+				if skipped == nil {
+					skipped = make(map[string]struct{})
+				}
+				skipped[name] = struct{}{}
+			`, g.useDuplicateNameError()))
+		}
+		g.writeMultiline(`
+			if err = d.SkipValue(); err != nil {
+				return err
+			}
+		`)
+	}
+	g.unindent()
+	g.writeLine("}")
+	g.unindent()
+	g.writeMultiline(`
+		}
+		if _, err = d.ReadToken(); err != nil {
+			return err
+		}
+	`)
+	if p != nil {
+		g.unmarshalerRequired(p, members, varExpr)
+	}
+}
+
+// unmarshalerFoldName maps the member name to the JSON name it matches
+// case-insensitively, through a switch on the folded names precomputed from
+// members. Names matching a member exactly are left unchanged, and folded
+// names shared by several foldable members are left out, so that they only
+// match exactly.
+func (g *generator) unmarshalerFoldName(members []jsonMember) {
+	type foldCase struct {
+		folded string
+		exact  []string // names of the members folding to folded
+		member jsonMember
+	}
+	folded := make(map[string][]jsonMember)
+	var keys []string
+	for _, m := range members {
+		f := foldName(m.name, g.cfg.IgnoreCase)
+		if _, ok := folded[f]; !ok {
+			keys = append(keys, f)
+		}
+		folded[f] = append(folded[f], m)
+	}
+	var cases []foldCase
+	optional := true // whether all cases depend on json.MatchCaseInsensitiveNames
+	for _, f := range keys {
+		c := foldCase{folded: f}
+		foldable := 0
+		for _, m := range folded[f] {
+			c.exact = append(c.exact, m.name)
+			if m.matchCase != "strict" {
+				c.member = m
+				foldable++
+			}
+		}
+		if foldable != 1 {
+			continue
+		}
+		if c.member.matchCase == "ignore" || g.cfg.IgnoreCase {
+			optional = false
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		return
+	}
+	if optional {
+		g.writeLine(fmt.Sprintf("if %s {", g.useOption("json.MatchCaseInsensitiveNames")))
+		g.indent()
+	}
+	g.writeLine(fmt.Sprintf("switch %s(name) {", g.useFoldName()))
+	for _, c := range cases {
+		g.writeLine(fmt.Sprintf("case %q:", c.folded))
+		g.indent()
+		var conds []string
+		if !optional && c.member.matchCase != "ignore" && !g.cfg.IgnoreCase {
+			conds = append(conds, g.useOption("json.MatchCaseInsensitiveNames"))
+		}
+		if len(c.exact) > 1 {
+			// Exact matches take precedence
+			for _, name := range c.exact {
+				conds = append(conds, fmt.Sprintf("name != %q", name))
+			}
+		}
+		if len(conds) > 0 {
+			g.writeLine(fmt.Sprintf("if %s {", strings.Join(conds, " && ")))
+			g.indent()
+		}
+		g.writeLine(fmt.Sprintf("name = %q", c.member.name))
+		if len(conds) > 0 {
+			g.unindent()
+			g.writeLine("}")
+		}
+		g.unindent()
+	}
+	g.writeLine("}")
+	if optional {
+		g.unindent()
+		g.writeLine("}")
+	}
+}
+
+// jsonMember is a member of a JSON object decoded into a struct.
+type jsonMember struct {
+	name      string // JSON name
+	matchCase string // value of the case tag option: "ignore", "strict" or ""
+	required  bool   // whether the member must be present
+}
+
+// jsonMembers returns the members of struct type ts, including the members
+// of embedded structs, in declaration order. With required set, members not
+// tagged omitempty or omitzero are required.
+func (g *generator) jsonMembers(ts *ast.StructType, required bool) []jsonMember {
+	visible := g.dominantFields(ts)
+	var members []jsonMember
+	var walk func(st *ast.StructType)
+	walk = func(st *ast.StructType) {
+		for _, field := range st.Fields.List {
+			jsonTag, jsonOpts := parseTag(field)
+			if jsonTag == "-" {
+				continue
+			}
+			if len(field.Names) == 0 || slices.Contains(jsonOpts, "inline") {
+				if embedded := g.embeddedStruct(field); embedded != nil {
+					walk(embedded)
+				}
+				continue
+			}
+			for _, name := range field.Names {
+				if !visible[name] {
+					continue
+				}
+				members = append(members, jsonMember{
+					name:      cmp.Or(jsonTag, name.Name),
+					matchCase: caseOption(jsonOpts),
+					required: slices.Contains(jsonOpts, "required") ||
+						required && !slices.Contains(jsonOpts, "omitempty") && !slices.Contains(jsonOpts, "omitzero"),
+				})
+			}
+		}
+	}
+	walk(ts)
+	return members
+}
+
+// embeddedStruct returns the struct type of the embedded or inline field,
+// or nil if it is not an exported struct type of the package.
+func (g *generator) embeddedStruct(field *ast.Field) *ast.StructType {
+	ident, ok := field.Type.(*ast.Ident)
+	if !ok || unicode.IsLower(rune(ident.Name[0])) {
+		return nil
+	}
+	typeSpec, ok := g.types[ident.Name]
+	if !ok {
+		return nil
+	}
+	st, _ := typeSpec.Type.(*ast.StructType)
+	return st
+}
+
+// dominantFields returns the set of the exported fields of struct type ts,
+// including the fields of embedded structs, that their JSON names refer to.
+// Like in json/v2, a name shared by several fields refers to the shallowest
+// one, or to the only one tagged with the name among the shallowest ones,
+// and otherwise to none of them.
+func (g *generator) dominantFields(ts *ast.StructType) map[*ast.Ident]bool {
+	type candidate struct {
+		ident  *ast.Ident
+		depth  int
+		tagged bool
+	}
+	byName := make(map[string][]candidate)
+	var walk func(st *ast.StructType, depth int)
+	walk = func(st *ast.StructType, depth int) {
+		for _, field := range st.Fields.List {
+			jsonTag, jsonOpts := parseTag(field)
+			if jsonTag == "-" {
+				continue
+			}
+			if len(field.Names) == 0 || slices.Contains(jsonOpts, "inline") {
+				if embedded := g.embeddedStruct(field); embedded != nil {
+					walk(embedded, depth+1)
+				}
+				continue
+			}
+			for _, name := range field.Names {
+				if name.IsExported() {
+					n := cmp.Or(jsonTag, name.Name)
+					byName[n] = append(byName[n], candidate{name, depth, jsonTag != ""})
+				}
+			}
+		}
+	}
+	walk(ts, 0)
+	dominant := make(map[*ast.Ident]bool)
+	for _, cs := range byName {
+		shallowest := slices.MinFunc(cs, func(a, b candidate) int { return cmp.Compare(a.depth, b.depth) }).depth
+		cs = slices.DeleteFunc(cs, func(c candidate) bool { return c.depth != shallowest })
+		if tagged := slices.DeleteFunc(slices.Clone(cs), func(c candidate) bool { return !c.tagged }); len(tagged) > 0 {
+			cs = tagged
+		}
+		if len(cs) == 1 {
+			dominant[cs[0].ident] = true
+		}
+	}
+	return dominant
+}
+
+// caseOption returns the value of the case option in jsonOpts, or "".
+func caseOption(jsonOpts []string) string {
+	for _, opt := range jsonOpts {
+		if value, ok := strings.CutPrefix(opt, "case:"); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// unmarshalerField emits the cases decoding the members of field, leaving
+// out the fields not in visible, which other fields shadow.
+func (g *generator) unmarshalerField(field *ast.Field, varExpr string, p *presence, visible map[*ast.Ident]bool) {
+	jsonTag, jsonOpts := parseTag(field)
+	if jsonTag == "-" {
+		// Skip this field
+		return
+	}
+	isEmbedded := len(field.Names) == 0
+	isInline := slices.Contains(jsonOpts, "inline")
+	if isEmbedded || isInline {
+		// Embedded or inline: recurse if struct
+		switch ts := field.Type.(type) {
+		case *ast.Ident:
+			if unicode.IsLower(rune(ts.Name[0])) {
+				// Skip unexported embedded field
+				return
+			}
+			typeSpec, ok := g.types[ts.Name]
+			if !ok {
+				fail(ts, "go-gen-json does not support external embedded types: %s", ts.Name)
+			}
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				fail(ts, "go-gen-json only supports embedded struct types: %s", ts.Name)
+			}
+			for _, f := range st.Fields.List {
+				g.unmarshalerField(f, varExpr+"."+ts.Name, p, visible)
+			}
+		// TODO:
+		// case *ast.SelectorExpr:
+		// case *ast.StarExpr:
+		// case *ast.StructType:
+		default:
+			fail(field.Type, "unsupported embedded or inline field type: %T", field.Type)
+		}
+		return
+	}
+	for _, name := range field.Names {
+		if !name.IsExported() {
+			// Skip unexported fields, like json/v2
+			continue
+		}
+		if !visible[name] {
+			// Skip shadowed fields
+			continue
+		}
+		typeString := g.typeString(field.Type)
+		g.writeLine(fmt.Sprintf(`case "%s":`, cmp.Or(jsonTag, name.Name)))
+		g.indent()
+		if p != nil {
+			g.unmarshalerPresence(p, cmp.Or(jsonTag, name.Name))
+		}
+		g.unmarshalerValue(field.Type, varExpr+"."+name.Name, func() {
+			if slices.Contains(jsonOpts, "format:number") {
+				if !g.isString(field.Type) {
+					fail(field.Type, "format:number is only supported for string fields: %s", name.Name)
+				}
+				g.unmarshalerNumberString(varExpr+"."+name.Name, typeString)
+			} else {
+				g.unmarshaler(typeString, field.Type, varExpr+"."+name.Name, typeString)
+			}
+		})
+		g.unindent()
+	}
+}
+
+// parseTag returns the JSON name and options from the json struct tag of
+// field. A name of "-" means the field is ignored.
+func parseTag(field *ast.Field) (jsonTag string, jsonOpts []string) {
+	value, err := jsonTagValue(field)
+	if err != nil {
+		fail(field.Tag, "parse json tag: %v", err)
+	}
+	if value == "" {
+		return "", nil
+	}
+	tags := strings.Split(value, ",")
+	return tags[0], tags[1:]
+}
+
+// jsonTagValue returns the value of the json struct tag of field, or "" if
+// it has none.
+func jsonTagValue(field *ast.Field) (string, error) {
+	if field.Tag == nil {
+		return "", nil
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(tag, " ")
+	idx := slices.IndexFunc(parts, func(part string) bool {
+		return strings.HasPrefix(part, "json:")
+	})
+	if idx == -1 {
+		return "", nil
+	}
+	return strconv.Unquote(strings.TrimPrefix(parts[idx], "json:"))
+}
+
+// isString reports whether the underlying type of expr is string.
+func (g *generator) isString(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if typeSpec, ok := g.types[ident.Name]; ok {
+		return g.isString(typeSpec.Type)
+	}
+	return ident.Name == "string"
+}
+
+func (g *generator) unmarshalerArray(elemType ast.Expr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler array: %s", varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler array: %s")`, varExpr))
+	}
+	typeString := g.typeString(elemType)
+	g.useTypeImports(elemType)
+	elem := g.tmpName("elem")
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		if t.Kind() != '[' {
+			return %[2]s
+		}
+	`, varExpr, g.unmarshalError(varExpr, "nil")))
+	g.unmarshalerDepth(varExpr)
+	g.writeMultiline(fmt.Sprintf(`
+		if %[1]s == nil {
+			%[1]s = []%[2]s{}
+		} else {
+			%[1]s = %[1]s[:0]
+		}
+		for d.PeekKind() != ']' {
+	`, varExpr, typeString))
+	g.indent()
+	g.unmarshalerCount("elements", "len("+varExpr+")", varExpr)
+	g.writeMultiline(fmt.Sprintf(`
+		var %[2]s %[3]s
+		if %[4]s && len(%[1]s) < cap(%[1]s) {
+			// Merge into the previous element
+			%[2]s = %[1]s[:len(%[1]s)+1][len(%[1]s)]
+		}
+	`, varExpr, elem, typeString, g.useOption("jsonv1.MergeWithLegacySemantics")))
+	g.depth++
+	g.unmarshalerValue(elemType, elem, func() { g.unmarshaler(typeString, elemType, elem, typeString) })
+	g.depth--
+	g.unindent()
+	g.writeMultiline(fmt.Sprintf(`
+			%[1]s = append(%[1]s, %[2]s)
+		}
+		if _, err = d.ReadToken(); err != nil {
+			return err
+		}
+	`, varExpr, elem))
+}
+
+func (g *generator) unmarshalerMap(typeName string, keyType ast.Expr, valueType ast.Expr, varExpr string) {
+	if kt, ok := keyType.(*ast.Ident); !ok || kt.Name != "string" {
+		fail(keyType, "JSON does not support non-string map keys")
+	}
+	valueTypeName := g.typeString(valueType)
+	g.useTypeImports(valueType)
+	key, value := g.tmpName("key"), g.tmpName("value")
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		if t.Kind() != '{' {
+			return %s
+		}
+	`, g.unmarshalError(varExpr, "nil")))
+	g.unmarshalerDepth(varExpr)
+	members := g.tmpName("members")
+	if g.cfg.limited() {
+		g.writeLine(members + " := 0")
+	}
+	// Keys are only tracked when duplicates are rejected regardless of
+	// options, since the Decoder rejects them otherwise
+	keys := g.tmpName("keys")
+	forceDuplicates := g.opts.rejectDuplicates || g.hasDirective(typeName, "rejectduplicates")
+	if forceDuplicates {
+		g.writeLine(fmt.Sprintf("%s := make(map[string]struct{})", keys))
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		if %[1]s == nil {
+			%[1]s = make(map[string]%[2]s)
+		}
+		for d.PeekKind() != '}' {
+	`, varExpr, valueTypeName))
+	g.indent()
+	if g.cfg.limited() {
+		g.unmarshalerCount("members", members, varExpr)
+		g.writeLine(members + "++")
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		t, err = d.ReadToken()
+		if err != nil {
+			return err
+		}
+		%[1]s := t.String()
+	`, key))
+	g.unmarshalerString(key, varExpr)
+	if forceDuplicates {
+		g.writeMultiline(fmt.Sprintf(`
+			if _, ok := %[1]s[%[2]s]; ok {
+				return %[3]s(d, t)
+			}
+			%[1]s[%[2]s] = struct{}{}
+		`, keys, key, g.useDuplicateNameError()))
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		var %[3]s %[2]s
+		if !%[4]s {
+			// Merge into the existing entry
+			%[3]s = %[1]s[%[5]s]
+		}
+	`, varExpr, valueTypeName, value, g.useOption("jsonv1.MergeWithLegacySemantics"), key))
+	g.depth++
+	g.unmarshalerValue(valueType, value, func() { g.unmarshaler(valueTypeName, valueType, value, valueTypeName) })
+	g.depth--
+	g.unindent()
+	g.writeMultiline(fmt.Sprintf(`
+			%s[%s] = %s
+		}
+		if _, err = d.ReadToken(); err != nil {
+			return err
+		}
+	`, varExpr, key, value))
+}
+
+func (g *generator) unmarshalerPointer(typeName string, ts *ast.StarExpr, varExpr string, originalName string) {
+	if g.cfg.Debug {
+		log.Printf("- unmarshaler pointer: %s (%s)", typeName, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler pointer: %s (%s)")`, typeName, varExpr))
+	}
+	// Read null into a nil pointer, otherwise initialize varExpr pointer and
+	// call unmarshaler on the underlying type
+	elemTypeName := g.typeString(ts.X)
+	g.useTypeImports(ts.X)
+	g.writeMultiline(fmt.Sprintf(`
+		if d.PeekKind() == 'n' {
+			t, err = d.ReadToken()
+			if err != nil {
+				return err
+			}
+			%[1]s = nil
+		} else {
+			if %[1]s == nil {
+				%[1]s = new(%[2]s)
+			}
+	`, varExpr, elemTypeName))
+	g.indent()
+	g.unmarshaler(elemTypeName, ts.X, fmt.Sprintf("(*%s)", varExpr), elemTypeName)
+	g.unindent()
+	g.writeLine("}")
+}
+
+// typeString returns the type string of expr as used in generated code, on a
+// single line and with package names replaced by the names the generated
+// file imports the packages by.
+func (g *generator) typeString(expr ast.Expr) string {
+	switch ts := expr.(type) {
+	case *ast.Ident:
+		return ts.Name
+	case *ast.SelectorExpr:
+		if x, ok := ts.X.(*ast.Ident); ok {
+			return g.imports.use(g.importPath(x)) + "." + ts.Sel.Name
+		}
+	case *ast.StarExpr:
+		return "*" + g.typeString(ts.X)
+	case *ast.ParenExpr:
+		return "(" + g.typeString(ts.X) + ")"
+	case *ast.ArrayType:
+		if ts.Len == nil {
+			return "[]" + g.typeString(ts.Elt)
+		}
+		return "[" + exprToString(ts.Len) + "]" + g.typeString(ts.Elt)
+	case *ast.MapType:
+		return "map[" + g.typeString(ts.Key) + "]" + g.typeString(ts.Value)
+	case *ast.StructType:
+		var fields []string
+		for _, field := range ts.Fields.List {
+			var names []string
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+			f := g.typeString(field.Type)
+			if len(names) > 0 {
+				f = strings.Join(names, ", ") + " " + f
+			}
+			if field.Tag != nil {
+				f += " " + field.Tag.Value
+			}
+			fields = append(fields, f)
+		}
+		if len(fields) == 0 {
+			return "struct{}"
+		}
+		return "struct{ " + strings.Join(fields, "; ") + " }"
+	}
+	return exprToString(expr)
+}
+
+// bitSize returns the size in bits of a sized numeric type name, or 0 for int
+// and uint.
+func bitSize(typeName string) int {
+	n, _ := strconv.Atoi(strings.TrimLeft(typeName, "intufloa"))
+	return n
+}
+
+// Hacky way to get type string
+func exprToString(expr ast.Expr) string {
+	buf := bytes.Buffer{}
+	printer.Fprint(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
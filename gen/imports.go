@@ -0,0 +1,155 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// fixedImports are the packages generated code refers to by fixed names. No
+// other package is imported under these names, whether they are used or not.
+var fixedImports = map[string]string{
+	"json":     "encoding/json/v2",
+	"jsontext": "encoding/json/jsontext",
+	"jsonv1":   "encoding/json",
+	"bytes":    "bytes",
+	"errors":   "errors",
+	"fmt":      "fmt",
+	"fnv":      "hash/fnv",
+	"io":       "io",
+	"iter":     "iter",
+	"log":      "log",
+	"maps":     "maps",
+	"math":     "math",
+	"reflect":  "reflect",
+	"slices":   "slices",
+	"strconv":  "strconv",
+	"strings":  "strings",
+	"unicode":  "unicode",
+	"utf8":     "unicode/utf8",
+}
+
+// importSet names the packages imported by a generated file. A package whose
+// name is taken by another package is aliased, e.g. a package json other than
+// encoding/json/v2 is imported as json2.
+type importSet struct {
+	names map[string]string // map of import paths to names
+	paths map[string]string // map of names to import paths
+}
+
+func newImportSet() *importSet {
+	return &importSet{names: make(map[string]string), paths: make(map[string]string)}
+}
+
+// use imports the package path, and returns the name generated code refers to
+// it by.
+func (s *importSet) use(path string) string {
+	if name, ok := s.names[path]; ok {
+		return name
+	}
+	name := identifier(packageName(path))
+	for fixed, fixedPath := range fixedImports {
+		if fixedPath == path {
+			name = fixed
+		}
+	}
+	for i, base := 2, name; s.taken(name, path); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	s.names[path] = name
+	s.paths[name] = path
+	return name
+}
+
+// taken reports whether name is the name of a package other than path.
+func (s *importSet) taken(name string, path string) bool {
+	if fixed, ok := fixedImports[name]; ok && fixed != path {
+		return true
+	}
+	if other, ok := s.paths[name]; ok && other != path {
+		return true
+	}
+	return token.IsKeyword(name)
+}
+
+// specs returns the import specs of the packages whose names are in used,
+// the standard library first, in path order. Packages are aliased unless
+// their name is the one the import path suggests.
+func (s *importSet) specs(used map[string]bool) []string {
+	var std, other []string
+	for _, path := range slices.Sorted(maps.Keys(s.names)) {
+		name := s.names[path]
+		if !used[name] {
+			continue
+		}
+		spec := fmt.Sprintf("%q", path)
+		if name != packageName(path) {
+			spec = name + " " + spec
+		}
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	if len(std) > 0 && len(other) > 0 {
+		std = append(std, "")
+	}
+	return append(std, other...)
+}
+
+// qualifiers returns the names qualifying identifiers in the Go source src,
+// those of the packages it uses.
+func qualifiers(src []byte) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				names[x.Name] = true
+			}
+		}
+		return true
+	})
+	return names, nil
+}
+
+// packageName returns the name of the package with the given import path,
+// assuming it is the last element of the path, or the one before a major
+// version suffix (e.g. json for encoding/json/v2).
+func packageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// Major version suffix, e.g. encoding/json/v2
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// identifier returns a Go identifier naming a package whose path ends with
+// name, e.g. yaml for gopkg.in/yaml.v3 or sqlite3 for go-sqlite3.
+func identifier(name string) string {
+	if i := strings.Index(name, ".v"); i > 0 && strings.Trim(name[i+2:], "0123456789") == "" {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "pkg" + name
+	}
+	return name
+}
@@ -0,0 +1,458 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// limitsFileName is the file declaring JSONLimits, shared by every type
+// generated with limits in the package.
+const limitsFileName = "jsonlimits_gen_json.go"
+
+// limited reports whether generated decoders enforce limits.
+func (o *Options) limited() bool {
+	return o.Limits || o.MaxDepth > 0 || o.MaxBytes > 0 || o.MaxElements > 0 || o.MaxMembers > 0 || o.MaxStringLength > 0
+}
+
+// limitsSource returns the file declaring JSONLimits in package p. Every type
+// generated with limits comes with the same file, so that it is declared once.
+func limitsSource(p *Package) ([]byte, error) {
+	g := &generator{name: p.Name, imports: newImportSet(), helpers: make(map[string]string)}
+	g.writeMultiline(`
+		// JSONLimits bound the input accepted by the UnmarshalJSONLimits
+		// methods. Zero fields are unlimited.
+		type JSONLimits struct {
+			MaxDepth        int // nesting depth of objects and arrays
+			MaxBytes        int // size of the whole input
+			MaxElements     int // elements per array
+			MaxMembers      int // members per object
+			MaxStringLength int // bytes per string, member names included
+		}
+	`)
+	return g.source()
+}
+
+// unmarshalerLimited writes UnmarshalJSON, UnmarshalJSONLimits and
+// UnmarshalJSONFrom, which decode with the method unmarshalJSONFrom whose
+// body is generated next, enforcing the default or given limits.
+func (g *generator) unmarshalerLimited(typeName string) {
+	g.useImports("reflect")
+	var defaults []string
+	for _, limit := range []struct {
+		field string
+		value int
+	}{
+		{"MaxDepth", g.cfg.MaxDepth},
+		{"MaxBytes", g.cfg.MaxBytes},
+		{"MaxElements", g.cfg.MaxElements},
+		{"MaxMembers", g.cfg.MaxMembers},
+		{"MaxStringLength", g.cfg.MaxStringLength},
+	} {
+		if limit.value > 0 {
+			defaults = append(defaults, fmt.Sprintf("%s: %d", limit.field, limit.value))
+		}
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		// %[2]s are the limits enforced by UnmarshalJSON and UnmarshalJSONFrom.
+		var %[2]s = JSONLimits{%[3]s}
+
+		func (p *%[1]s) UnmarshalJSON(b []byte) error {
+			return p.UnmarshalJSONLimits(b, %[2]s)
+		}
+
+		// UnmarshalJSONLimits decodes b into p like UnmarshalJSON, enforcing
+		// limits instead of the ones set when generating, and with opts.
+		func (p *%[1]s) UnmarshalJSONLimits(b []byte, limits JSONLimits, opts ...json.Options) error {
+			if limits.MaxBytes > 0 && len(b) > limits.MaxBytes {
+				return %[5]s(nil, reflect.TypeOf(p).Elem(), "more than %%d bytes of input", limits.MaxBytes)
+			}
+			return %[6]s(b, json.Unmarshal(b, &%[4]s{p, &limits}, opts...))
+		}
+
+		// %[4]s decodes into p enforcing limits.
+		type %[4]s struct {
+			p      *%[1]s
+			limits *JSONLimits
+		}
+
+		func (l *%[4]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+			return l.p.unmarshalJSONFrom(d, l.limits)
+		}
+
+		func (p *%[1]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
+			return p.unmarshalJSONFrom(d, &%[2]s)
+		}
+
+		func (p *%[1]s) unmarshalJSONFrom(d *jsontext.Decoder, limits *JSONLimits) error {
+	`, typeName, g.limitsVar(), strings.Join(defaults, ", "), "limited"+typeName, g.useLimitError(), g.usePositionError()))
+}
+
+// limitsVar returns the name of the variable holding the default limits of
+// the generated type.
+func (g *generator) limitsVar() string {
+	return "jsonLimits" + g.typeName
+}
+
+// unmarshalerDepth rejects the object or array just opened into varExpr if
+// it is nested too deep.
+func (g *generator) unmarshalerDepth(varExpr string) {
+	if !g.cfg.limited() {
+		return
+	}
+	g.writeLimit("limits.MaxDepth > 0 && d.StackDepth()-baseDepth > limits.MaxDepth", varExpr, "nesting deeper than %d", "limits.MaxDepth")
+}
+
+// unmarshalerCount rejects the next member or element of the object or array
+// decoded into varExpr if count, the number decoded so far, reaches the limit
+// on kind ("members" or "elements"), or if the input read so far is too long.
+func (g *generator) unmarshalerCount(kind string, count string, varExpr string) {
+	if !g.cfg.limited() {
+		return
+	}
+	g.writeLimit("limits.MaxBytes > 0 && d.InputOffset() > int64(limits.MaxBytes)", varExpr, "more than %d bytes of input", "limits.MaxBytes")
+	field, container := "limits.MaxMembers", "object"
+	if kind == "elements" {
+		field, container = "limits.MaxElements", "array"
+	}
+	g.writeLimit(fmt.Sprintf("%[1]s > 0 && %[2]s >= %[1]s", field, count), varExpr, "more than %d "+kind+" in "+container, field)
+}
+
+// unmarshalerString rejects the string s just read for varExpr if it is too
+// long.
+func (g *generator) unmarshalerString(s string, varExpr string) {
+	if !g.cfg.limited() {
+		return
+	}
+	g.writeLimit(fmt.Sprintf("limits.MaxStringLength > 0 && len(%s) > limits.MaxStringLength", s), varExpr, "string longer than %d bytes", "limits.MaxStringLength")
+}
+
+// writeLimit returns the error for exceeding limit while decoding varExpr if
+// cond holds.
+func (g *generator) writeLimit(cond string, varExpr string, format string, limit string) {
+	g.useImports("reflect")
+	g.writeMultiline(fmt.Sprintf(`
+		if %s {
+			return %s(d, reflect.TypeOf(&%s).Elem(), %q, %s)
+		}
+	`, cond, g.useLimitError(), varExpr, format, limit))
+}
+
+// limitChecks returns the code of the unmarshalAny helper enforcing limits
+// with the helper limitError, indented to its body: when opening an object or
+// array, at each member or element, and for member names and strings, which
+// value decodes.
+func limitChecks(limitError string) (depth, members, elements, key, value string) {
+	check := func(lvl int, cond string, format string, limit string) string {
+		indent := strings.Repeat("\t", lvl)
+		return fmt.Sprintf("\n%[1]sif %[2]s {\n%[1]s\treturn nil, %[3]s(d, reflect.TypeOf(&v).Elem(), %[4]q, %[5]s)\n%[1]s}",
+			indent, cond, limitError, format, limit)
+	}
+	bytes := "limits.MaxBytes > 0 && d.InputOffset() > int64(limits.MaxBytes)"
+	depth = check(5, "limits.MaxDepth > 0 && d.StackDepth()-baseDepth > limits.MaxDepth", "nesting deeper than %d", "limits.MaxDepth")
+	members = check(6, bytes, "more than %d bytes of input", "limits.MaxBytes") +
+		check(6, "limits.MaxMembers > 0 && len(m) >= limits.MaxMembers", "more than %d members in object", "limits.MaxMembers")
+	elements = check(6, bytes, "more than %d bytes of input", "limits.MaxBytes") +
+		check(6, "limits.MaxElements > 0 && len(s) >= limits.MaxElements", "more than %d elements in array", "limits.MaxElements")
+	key = check(6, "limits.MaxStringLength > 0 && len(key) > limits.MaxStringLength", "string longer than %d bytes", "limits.MaxStringLength")
+	value = "\n\t\t\t\t\ts := t.String()" +
+		check(5, "limits.MaxStringLength > 0 && len(s) > limits.MaxStringLength", "string longer than %d bytes", "limits.MaxStringLength") +
+		"\n\t\t\t\t\treturn s, nil"
+	return
+}
+
+// useLimitError emits a helper building the error for input exceeding a
+// limit, wrapping the sentinel error of useErrLimit. It returns the name of
+// the helper.
+func (g *generator) useLimitError() string {
+	return g.useHelper("limitError", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "fmt", "reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the error for the input read from d, or the whole
+			// input if d is nil, exceeding limit while decoding a value of type
+			// goType. format describes the limit.
+			func %[1]s(d *jsontext.Decoder, goType reflect.Type, format string, limit int) error {
+				serr := &json.SemanticError{
+					GoType: goType,
+					Err:    fmt.Errorf("%%w: "+format, %[2]s, limit),
+				}
+				if d == nil {
+					// Rejected before json.Unmarshal annotates it
+					return %[3]s(nil, goType, serr)
+				}
+				serr.ByteOffset = d.InputOffset()
+				serr.JSONPointer = d.StackPointer()
+				return serr
+			}
+		`, name, h.useErrLimit(), h.useAnnotateError()))
+	})
+}
+
+// useLimitUnmarshalers emits a helper returning the options under which
+// json/v2 decodes into an existing value, enforcing limits on the values it
+// decodes as the unmarshalAny helper does. It returns the name of the helper.
+func (g *generator) useLimitUnmarshalers() string {
+	return g.useHelper("limitUnmarshalers", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the options enforcing limits on each value json/v2
+			// decodes from d, before the unmarshalers of the options of d.
+			// Depths are counted from baseDepth.
+			func %[1]s(d *jsontext.Decoder, limits *JSONLimits, baseDepth int) json.Options {
+				var decoding bool // a string already checked
+				check := json.UnmarshalFromFunc(func(d *jsontext.Decoder, p any) error {
+					if decoding {
+						return errors.ErrUnsupported
+					}
+					goType := reflect.TypeOf(p).Elem()
+					depth := d.StackDepth()
+					kind, length := d.StackIndex(depth)
+					switch {
+					case limits.MaxBytes > 0 && d.InputOffset() > int64(limits.MaxBytes):
+						return %[2]s(d, goType, "more than %%d bytes of input", limits.MaxBytes)
+					case kind == '[' && limits.MaxElements > 0 && length >= int64(limits.MaxElements):
+						return %[2]s(d, goType, "more than %%d elements in array", limits.MaxElements)
+					case kind == '{' && limits.MaxMembers > 0 && length/2 >= int64(limits.MaxMembers):
+						return %[2]s(d, goType, "more than %%d members in object", limits.MaxMembers)
+					case kind == '{' && limits.MaxStringLength > 0 && len(d.StackPointer().LastToken()) > limits.MaxStringLength:
+						return %[2]s(d, goType, "string longer than %%d bytes", limits.MaxStringLength)
+					}
+					switch d.PeekKind() {
+					case '{', '[':
+						if limits.MaxDepth > 0 && depth-baseDepth >= limits.MaxDepth {
+							// Reported within the object or array, like unmarshalAny
+							if _, err := d.ReadToken(); err != nil {
+								return err
+							}
+							return %[2]s(d, goType, "nesting deeper than %%d", limits.MaxDepth)
+						}
+					case '"':
+						if limits.MaxStringLength <= 0 {
+							break
+						}
+						// The length of a string is known once it is read
+						raw, err := d.ReadValue()
+						if err != nil {
+							return err
+						}
+						s, err := jsontext.AppendUnquote(nil, raw)
+						if err != nil {
+							return err
+						}
+						if len(s) > limits.MaxStringLength {
+							return %[2]s(d, goType, "string longer than %%d bytes", limits.MaxStringLength)
+						}
+						decoding = true
+						err = json.Unmarshal(raw, p, d.Options())
+						decoding = false
+						var serr *json.SemanticError
+						if errors.As(err, &serr) {
+							serr.ByteOffset = d.InputOffset() - int64(len(raw))
+							serr.JSONPointer = d.StackPointer()
+						}
+						return err
+					}
+					return errors.ErrUnsupported
+				})
+				if others, _ := json.GetOption(d.Options(), json.WithUnmarshalers); others != nil {
+					check = json.JoinUnmarshalers(check, others)
+				}
+				return json.WithUnmarshalers(check)
+			}
+		`, name, h.useLimitError()))
+	})
+}
+
+// useErrLimit emits the sentinel error wrapped by the errors for input
+// exceeding limits. It returns the name of the variable.
+func (g *generator) useErrLimit() string {
+	return g.useHelper("errLimit", func(h *generator, name string) {
+		h.useImports("errors")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s is wrapped by the errors for input exceeding limits.
+			var %[1]s = errors.New("limit exceeded")
+		`, name))
+	})
+}
+
+// unmarshalerLimits declares the limits variable of a method decoding with
+// the default limits, when it is not a parameter.
+func (g *generator) unmarshalerLimits() {
+	if g.cfg.limited() {
+		g.writeLine("limits := &" + g.limitsVar())
+	}
+}
+
+// unmarshalerBaseDepth declares the depth of the Decoder where the method
+// starts decoding, from which MaxDepth is counted, if code uses it.
+func (g *generator) unmarshalerBaseDepth(code string) {
+	if g.cfg.limited() && usesIdent(code, "baseDepth") {
+		g.writeLine("baseDepth := d.StackDepth()")
+	}
+}
+
+// limitsArg returns the limits arguments passed to the unmarshalAny helper.
+func (g *generator) limitsArg() string {
+	if !g.cfg.limited() {
+		return ""
+	}
+	return ", limits, baseDepth"
+}
+
+// useDecodeLimited emits a helper decoding a value with json/v2 reflection,
+// enforcing limits like the generated methods: the input is scanned first,
+// following the fields of the type as json/v2 decodes them. It returns the
+// name of the helper.
+func (g *generator) useDecodeLimited() string {
+	return g.useHelper("decodeLimited", func(h *generator, name string) {
+		h.useImports("bytes", "encoding", "encoding/json/jsontext", "encoding/json/v2", "errors", "fmt", "reflect")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s decodes the next value of d into v, a pointer to a value of
+			// type goType without methods, with opts. Input exceeding limits in
+			// the value, or in the values of its fields, elements and members
+			// json/v2 decodes without methods, is rejected first.
+			func %[1]s(d *jsontext.Decoder, v any, goType reflect.Type, limits *JSONLimits, opts ...json.Options) error {
+				raw, err := d.ReadValue()
+				if err != nil {
+					return err
+				}
+				// Errors are located in the input of d
+				base, offset := d.StackPointer(), d.InputOffset()-int64(len(raw))
+				s := jsontext.NewDecoder(bytes.NewReader(raw))
+				fail := func(t reflect.Type, format string, limit int) error {
+					return &json.SemanticError{
+						ByteOffset:  offset + s.InputOffset(),
+						JSONPointer: base + s.StackPointer(),
+						GoType:      t,
+						Err:         fmt.Errorf("%%w: "+format, %[2]s, limit),
+					}
+				}
+				var scan func(t reflect.Type) error
+				scan = func(t reflect.Type) error {
+					for t.Kind() == reflect.Pointer {
+						t = t.Elem()
+					}
+					kind := s.PeekKind()
+					switch {
+					case kind == '"' && (t.Kind() == reflect.String || t.Kind() == reflect.Interface):
+						tok, err := s.ReadToken()
+						if err != nil {
+							return err
+						}
+						if limits.MaxStringLength > 0 && len(tok.String()) > limits.MaxStringLength {
+							return fail(t, "string longer than %%d bytes", limits.MaxStringLength)
+						}
+						return nil
+					case kind == '{' && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map || t.Kind() == reflect.Interface):
+					case kind == '[' && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Interface):
+					default:
+						// Left to json/v2, which rejects values of the wrong
+						// kind before their contents
+						return s.SkipValue()
+					}
+					if _, err := s.ReadToken(); err != nil {
+						return err
+					}
+					if limits.MaxDepth > 0 && s.StackDepth() > limits.MaxDepth {
+						return fail(t, "nesting deeper than %%d", limits.MaxDepth)
+					}
+					for n := 0; s.PeekKind() != '}' && s.PeekKind() != ']'; n++ {
+						switch {
+						case limits.MaxBytes > 0 && offset+s.InputOffset() > int64(limits.MaxBytes):
+							return fail(t, "more than %%d bytes of input", limits.MaxBytes)
+						case kind == '{' && limits.MaxMembers > 0 && n >= limits.MaxMembers:
+							return fail(t, "more than %%d members in object", limits.MaxMembers)
+						case kind == '[' && limits.MaxElements > 0 && n >= limits.MaxElements:
+							return fail(t, "more than %%d elements in array", limits.MaxElements)
+						}
+						elem := t
+						if kind == '{' {
+							tok, err := s.ReadToken()
+							if err != nil {
+								return err
+							}
+							if limits.MaxStringLength > 0 && len(tok.String()) > limits.MaxStringLength {
+								return fail(t, "string longer than %%d bytes", limits.MaxStringLength)
+							}
+							if t.Kind() == reflect.Struct {
+								elem = %[3]s(t, tok.String())
+							}
+						}
+						if t.Kind() == reflect.Map || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+							elem = t.Elem()
+						}
+						for elem != nil && elem.Kind() == reflect.Pointer {
+							elem = elem.Elem()
+						}
+						// Unknown members, and values decoded by their methods
+						// with limits of their own, if any
+						if elem == nil || reflect.PointerTo(elem).Implements(reflect.TypeFor[json.UnmarshalerFrom]()) ||
+							reflect.PointerTo(elem).Implements(reflect.TypeFor[json.Unmarshaler]()) ||
+							reflect.PointerTo(elem).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+							if err := s.SkipValue(); err != nil {
+								return err
+							}
+							continue
+						}
+						if err := scan(elem); err != nil {
+							return err
+						}
+					}
+					_, err := s.ReadToken()
+					return err
+				}
+				if err := scan(goType); err != nil {
+					return err
+				}
+				err = json.Unmarshal(raw, v, append([]json.Options{d.Options()}, opts...)...)
+				if serr := (*json.SemanticError)(nil); errors.As(err, &serr) {
+					serr.ByteOffset += offset
+					serr.JSONPointer = base + serr.JSONPointer
+				}
+				return err
+			}
+		`, name, h.useErrLimit(), h.useFieldType()))
+	})
+}
+
+// useFieldType emits a helper resolving the field of a struct type json/v2
+// decodes a member into. It returns the name of the helper.
+func (g *generator) useFieldType() string {
+	return g.useHelper("fieldType", func(h *generator, name string) {
+		h.useImports("cmp", "reflect", "slices", "strings")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the type of the field of the struct type t named
+			// name in JSON, looking into inlined fields after the others, or
+			// the type of the unknown members, nil if they are skipped.
+			func %[1]s(t reflect.Type, name string) reflect.Type {
+				var inlined []reflect.Type
+				var unknown reflect.Type
+				for i := range t.NumField() {
+					f := t.Field(i)
+					tag, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+					ft := f.Type
+					for ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					inline := f.Anonymous && tag == "" || slices.Contains(strings.Split(opts, ","), "inline")
+					switch {
+					case f.Tag.Get("json") == "-":
+					case inline && ft.Kind() == reflect.Struct:
+						inlined = append(inlined, ft)
+					case inline && ft.Kind() == reflect.Map:
+						// Holding the unknown members
+						unknown = ft.Elem()
+					case !f.IsExported():
+					case cmp.Or(tag, f.Name) == name:
+						return f.Type
+					}
+				}
+				for _, t := range inlined {
+					if ft := %[1]s(t, name); ft != nil {
+						return ft
+					}
+				}
+				return unknown
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,132 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// marker is the doc comment marker selecting a type for generation, besides
+// the codec directive. Both may be followed by options, e.g.
+// "//json:generate strict marshal-only".
+const marker = "//json:generate"
+
+// directivePrefixes are the prefixes of directives such as
+// //gogenjson:codec. gofmt only keeps comments without hyphens as directives,
+// and turns //go-gen-json:codec into // go-gen-json:codec, which is accepted
+// too so that formatting does not drop directives of existing code.
+var directivePrefixes = []string{"//gogenjson:", "//go-gen-json:", "// go-gen-json:"}
+
+// directive returns the text following the directive name in doc, and
+// whether there is one.
+func directive(doc *ast.CommentGroup, name string) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, c := range doc.List {
+		for _, prefix := range directivePrefixes {
+			if text, ok := strings.CutPrefix(c.Text, prefix); ok {
+				if text, ok := cutWord(text, name); ok {
+					return text, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// cutWord returns s without its prefix word, and whether s starts with word
+// followed by a space or nothing.
+func cutWord(s string, word string) (string, bool) {
+	text, ok := strings.CutPrefix(s, word)
+	return text, ok && (text == "" || text[0] == ' ' || text[0] == '\t')
+}
+
+// typeOptions are the options a type is generated with: the Options of every
+// type, overridden by the options of its marker.
+type typeOptions struct {
+	rejectUnknown    bool // reject unknown members regardless of options
+	rejectDuplicates bool // reject duplicate names regardless of options
+	deterministic    bool // sort map keys regardless of options
+	marshalOnly      bool // generate no decoding methods
+	collect          bool // generate UnmarshalJSONCollect
+}
+
+// typeOptions returns the options of types without marker options.
+func (o *Options) typeOptions() typeOptions {
+	return typeOptions{
+		rejectUnknown:    o.RejectUnknown,
+		rejectDuplicates: o.RejectDuplicates,
+		deterministic:    o.Deterministic,
+		marshalOnly:      o.MarshalOnly,
+		collect:          o.Collect,
+	}
+}
+
+// marked returns the text following the marker or codec directive of the
+// type ts, and whether it is marked.
+func marked(ts *ast.TypeSpec) (string, bool) {
+	if ts.Doc == nil {
+		return "", false
+	}
+	for _, c := range ts.Doc.List {
+		if text, ok := cutWord(c.Text, marker); ok {
+			return text, true
+		}
+	}
+	return directive(ts.Doc, "codec")
+}
+
+// typeOptions returns the options of the type ts, and a problem if its marker
+// has invalid options. Options are set with their name, or name=false to
+// override a flag.
+func (g *generator) typeOptions(ts *ast.TypeSpec) (typeOptions, *Diagnostic) {
+	opts := g.cfg.typeOptions()
+	text, ok := marked(ts)
+	if !ok {
+		return opts, nil
+	}
+	for _, field := range strings.Fields(text) {
+		name, value, hasValue := strings.Cut(field, "=")
+		on := true
+		if hasValue {
+			var err error
+			if on, err = strconv.ParseBool(value); err != nil {
+				return opts, g.markerProblem(ts, "invalid value %q of marker option %s (use true or false)", value, name)
+			}
+		}
+		switch name {
+		case "strict":
+			opts.rejectUnknown, opts.rejectDuplicates = on, on
+		case "deterministic":
+			opts.deterministic = on
+		case "marshal-only":
+			opts.marshalOnly = on
+		case "collect":
+			opts.collect = on
+		default:
+			return opts, g.markerProblem(ts, "unknown marker option %q (use strict, deterministic, marshal-only or collect)", name)
+		}
+	}
+	return opts, nil
+}
+
+// markerProblem returns a problem with the marker of the type ts like the
+// problems reported by Validate.
+func (g *generator) markerProblem(ts *ast.TypeSpec, format string, args ...any) *Diagnostic {
+	return &Diagnostic{Pos: g.fset.Position(ts.Pos()), Message: ts.Name.Name + ": " + fmt.Sprintf(format, args...)}
+}
+
+// MarkedTypes returns the names of the types of package p marked for
+// generation, in the order they are declared.
+func MarkedTypes(p *Package) []string {
+	var names []string
+	for name, ts := range p.Types {
+		if _, ok := marked(ts); ok {
+			names = append(names, name)
+		}
+	}
+	sortByPosition(p, names)
+	return names
+}
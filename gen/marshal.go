@@ -0,0 +1,606 @@
+package gen
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+func (g *generator) GenerateMarshalJSON(typeName string, typeExpr ast.Expr) {
+	if g.cfg.Debug {
+		g.useImports("log")
+	}
+	g.useImports("encoding/json/jsontext", "encoding/json/v2")
+	g.writeLine("")
+	// MarshalJSON goes through json.Marshal, so that errors are annotated the
+	// same way as when MarshalJSONTo is called by json/v2.
+	g.writeMultiline(fmt.Sprintf(`
+		func (p *%[1]s) MarshalJSON() ([]byte, error) {
+			return json.Marshal(p)
+		}
+
+		func (p *%[1]s) MarshalJSONTo(e *jsontext.Encoder) error {
+	`, typeName))
+	g.indent()
+	code := g.capture(func() { g.marshaler(typeName, typeExpr, "(*p)") })
+	g.declareOptions("e")
+	// The shared err is only declared when assigned to, since a named type
+	// such as time.Time declares its own.
+	if strings.Contains(code, "err = ") {
+		g.writeLine("var err error")
+	}
+	g.body.WriteString(code)
+	g.writeLine("return nil")
+	g.unindent()
+	g.writeLine("}")
+}
+
+func (g *generator) marshaler(typeName string, typeExpr ast.Expr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler: %s", typeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler: %s")`, typeName))
+	}
+	switch ts := typeExpr.(type) {
+	case *ast.Ident:
+		g.marshalerIdent(ts.Name, varExpr)
+	case *ast.SelectorExpr:
+		g.marshalerSelector(typeName, ts, varExpr)
+	case *ast.StructType:
+		g.marshalerStruct(typeName, ts, varExpr)
+	case *ast.ArrayType:
+		g.marshalerArray(ts.Elt, varExpr)
+	case *ast.MapType:
+		g.marshalerMap(ts.Key, ts.Value, varExpr)
+	case *ast.StarExpr:
+		g.marshalerPointer(ts, varExpr)
+	default:
+		fail(typeExpr, "not implemented for type: %T", ts)
+	}
+}
+
+// writeToken writes code encoding the token constructed by tokenExpr.
+func (g *generator) writeToken(tokenExpr string) {
+	g.writeMultiline(fmt.Sprintf(`
+		if err = e.WriteToken(%s); err != nil {
+			return err
+		}
+	`, tokenExpr))
+}
+
+// writeNumber writes the JSON number tokenExpr, or with json.StringifyNumbers
+// a JSON string holding it.
+func (g *generator) writeNumber(tokenExpr string) {
+	g.writeMultiline(fmt.Sprintf(`
+		if !%[1]s {
+			err = e.WriteToken(%[2]s)
+		} else {
+			err = e.WriteToken(jsontext.String(%[2]s.String()))
+		}
+		if err != nil {
+			return err
+		}
+	`, g.useOption("json.StringifyNumbers"), tokenExpr))
+}
+
+func (g *generator) marshalerIdent(typeName string, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler ident: %s (%s)", typeName, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler ident: %s (%s)")`, typeName, varExpr))
+	}
+	typeName = predeclaredShape(typeName)
+	switch typeName {
+	case "string":
+		g.writeToken(fmt.Sprintf("jsontext.String(string(%s))", varExpr))
+	case "int", "int8", "int16", "int32", "int64":
+		g.writeNumber(fmt.Sprintf("jsontext.Int(int64(%s))", varExpr))
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		g.writeNumber(fmt.Sprintf("jsontext.Uint(uint64(%s))", varExpr))
+	case "bool":
+		g.writeToken(fmt.Sprintf("jsontext.Bool(bool(%s))", varExpr))
+	case "float32", "float64":
+		g.useImports("errors", "math", "strconv")
+		g.writeMultiline(fmt.Sprintf(`
+			if math.IsNaN(float64(%[1]s)) || math.IsInf(float64(%[1]s), 0) {
+				return %[2]s
+			}
+		`, varExpr, g.marshalError(varExpr, fmt.Sprintf(`errors.New("unsupported value: " + strconv.FormatFloat(float64(%s), 'g', -1, 64))`, varExpr))))
+		if typeName == "float32" {
+			g.writeNumber(fmt.Sprintf("jsontext.Float32(float32(%s))", varExpr))
+		} else {
+			g.writeNumber(fmt.Sprintf("jsontext.Float(float64(%s))", varExpr))
+		}
+	case "any":
+		g.writeMultiline(fmt.Sprintf(`
+			if err = %s(e, %s, %t); err != nil {
+				return err
+			}
+		`, g.useMarshalAny(), varExpr, g.opts.deterministic))
+	default:
+		if typeSpec, ok := g.types[typeName]; ok {
+			defer g.inFile(typeName)()
+			g.marshaler(typeSpec.Name.Name, typeSpec.Type, varExpr)
+		} else {
+			fail(nil, "unrecognized type: %s", typeName)
+		}
+	}
+}
+
+func (g *generator) marshalerSelector(typeName string, expr *ast.SelectorExpr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler selector: %s (%s)", typeName, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler selector: %s (%s)")`, typeName, varExpr))
+	}
+	X, ok := expr.X.(*ast.Ident)
+	if !ok {
+		fail(expr, "go-gen-json does not support non-Time selector expr")
+	}
+	switch path := g.importPath(X) + "." + expr.Sel.Name; {
+	case g.cfg.Codecs[path] == "json":
+		g.writeMultiline(fmt.Sprintf(`
+			if err := json.MarshalEncode(e, %s); err != nil {
+				return err
+			}
+		`, g.addrAsType(expr, typeName, varExpr)))
+	case g.cfg.Codecs[path] == "text" || path == "time.Time" && g.cfg.TimeFormat == "":
+		g.writeMultiline(fmt.Sprintf(`
+			if b, err := %s.MarshalText(); err != nil {
+				return %s
+			} else if err := e.WriteToken(jsontext.String(string(b))); err != nil {
+				return err
+			}
+		`, g.asType(expr, typeName, varExpr), g.marshalError(varExpr, "err")))
+	case path == "time.Time":
+		g.writeMultiline(fmt.Sprintf(`
+			if err := e.WriteToken(jsontext.String(%s.Format(%s))); err != nil {
+				return err
+			}
+		`, g.asType(expr, typeName, varExpr), g.timeLayout()))
+	case path == "encoding/json.Number":
+		g.marshalerNumberString(varExpr)
+	case path == "math/big.Int", path == "math/big.Float", path == "math/big.Rat":
+		g.marshalerBig(expr.Sel.Name, varExpr, g.asType(expr, typeName, varExpr))
+	default:
+		fail(expr, "go-gen-json does not support external packages")
+	}
+}
+
+// marshalerNumberString encodes a string type holding the text of a JSON
+// number, such as json.Number or a string with the format:number option, as a
+// bare JSON number. Like encoding/json, an empty string is encoded as 0.
+func (g *generator) marshalerNumberString(varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler number string: %s", varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler number string: %s")`, varExpr))
+	}
+	g.useImports("errors", "strconv", "strings")
+	g.writeMultiline(fmt.Sprintf(`
+		if s := string(%s); s == "" {
+			err = e.WriteToken(jsontext.Int(0))
+		} else if strings.TrimSpace(s) != s || jsontext.Value(s).Kind() != '0' || !jsontext.Value(s).IsValid() {
+			return %s
+		} else {
+			err = e.WriteValue(jsontext.Value(s))
+		}
+		if err != nil {
+			return err
+		}
+	`, varExpr, g.marshalError(varExpr, `errors.New("invalid number literal: " + strconv.Quote(s))`)))
+}
+
+// marshalerBig encodes a big.Int, big.Float or big.Rat as a bare JSON number
+// with all of its digits. valueExpr is varExpr converted to the big type.
+func (g *generator) marshalerBig(kind string, varExpr string, valueExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler big: %s (%s)", kind, varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler big: %s (%s)")`, kind, varExpr))
+	}
+	switch kind {
+	case "Int":
+		g.writeMultiline(fmt.Sprintf(`
+			if err = e.WriteValue(%s.Append(e.AvailableBuffer(), 10)); err != nil {
+				return err
+			}
+		`, valueExpr))
+	case "Float":
+		g.useImports("errors")
+		g.writeMultiline(fmt.Sprintf(`
+			if %[1]s.IsInf() {
+				return %[2]s
+			}
+			if err = e.WriteValue(%[1]s.Append(e.AvailableBuffer(), 'g', -1)); err != nil {
+				return err
+			}
+		`, valueExpr, g.marshalError(varExpr, fmt.Sprintf(`errors.New("unsupported value: " + %s.String())`, valueExpr))))
+	case "Rat":
+		g.useImports("errors")
+		g.writeMultiline(fmt.Sprintf(`
+			if n, exact := %[1]s.FloatPrec(); !exact {
+				return %[2]s
+			} else if err = e.WriteValue(jsontext.Value(%[1]s.FloatString(n))); err != nil {
+				return err
+			}
+		`, valueExpr, g.marshalError(varExpr, fmt.Sprintf(`errors.New("unsupported value: " + %s.String() + " has no finite decimal representation")`, valueExpr))))
+	}
+}
+
+func (g *generator) marshalerStruct(typeName string, ts *ast.StructType, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler struct: %s", typeName)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler struct: %s")`, typeName))
+	}
+	g.writeToken("jsontext.BeginObject")
+	visible := g.dominantFields(ts)
+	for _, field := range ts.Fields.List {
+		g.marshalerField(field, varExpr, visible)
+	}
+	g.writeToken("jsontext.EndObject")
+}
+
+// marshalerField emits the encoding of the members of field, leaving out the
+// fields not in visible, which other fields shadow.
+func (g *generator) marshalerField(field *ast.Field, varExpr string, visible map[*ast.Ident]bool) {
+	jsonTag, jsonOpts := parseTag(field)
+	if jsonTag == "-" {
+		// Skip this field
+		return
+	}
+	isEmbedded := len(field.Names) == 0
+	isInline := slices.Contains(jsonOpts, "inline")
+	if isEmbedded || isInline {
+		// Embedded or inline: recurse if struct
+		switch ts := field.Type.(type) {
+		case *ast.Ident:
+			if unicode.IsLower(rune(ts.Name[0])) {
+				// Skip unexported embedded field
+				return
+			}
+			typeSpec, ok := g.types[ts.Name]
+			if !ok {
+				fail(ts, "go-gen-json does not support external embedded types: %s", ts.Name)
+			}
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				fail(ts, "go-gen-json only supports embedded struct types: %s", ts.Name)
+			}
+			for _, f := range st.Fields.List {
+				g.marshalerField(f, varExpr+"."+ts.Name, visible)
+			}
+		default:
+			fail(field.Type, "unsupported embedded or inline field type: %T", field.Type)
+		}
+		return
+	}
+	for _, name := range field.Names {
+		if !name.IsExported() {
+			// Skip unexported fields, like json/v2
+			continue
+		}
+		if !visible[name] {
+			// Skip shadowed fields
+			continue
+		}
+		fieldExpr := varExpr + "." + name.Name
+		omit := g.omitCond(field.Type, jsonOpts, fieldExpr)
+		if omit != "" {
+			g.writeLine(fmt.Sprintf("if !(%s) {", omit))
+			g.indent()
+		}
+		g.writeToken(fmt.Sprintf("jsontext.String(%q)", cmp.Or(jsonTag, name.Name)))
+		if slices.Contains(jsonOpts, "format:number") {
+			if !g.isString(field.Type) {
+				fail(field.Type, "format:number is only supported for string fields: %s", name.Name)
+			}
+			g.marshalerNumberString(fieldExpr)
+		} else {
+			g.marshaler(g.typeString(field.Type), field.Type, fieldExpr)
+		}
+		if omit != "" {
+			g.unindent()
+			g.writeLine("}")
+		}
+	}
+}
+
+// omitCond returns a condition reporting whether the field varExpr of type
+// typeExpr with tag options jsonOpts is omitted from its object.
+func (g *generator) omitCond(typeExpr ast.Expr, jsonOpts []string, varExpr string) string {
+	zero, omit := g.omitConds(typeExpr, jsonOpts, varExpr)
+	if zero != "" {
+		omit = append(omit, fmt.Sprintf("%s && %s", zero, g.lazyOption("e", "json.OmitZeroStructFields")))
+	}
+	if len(omit) == 1 {
+		return omit[0]
+	}
+	for i, cond := range omit {
+		omit[i] = "(" + cond + ")"
+	}
+	return strings.Join(omit, " || ")
+}
+
+// omitConds returns the conditions under which the field varExpr is omitted:
+// zero is the zero value condition applying with json.OmitZeroStructFields
+// ("" if the field is omitted when zero anyway), and omit those applying
+// regardless of options.
+func (g *generator) omitConds(typeExpr ast.Expr, jsonOpts []string, varExpr string) (zero string, omit []string) {
+	if slices.Contains(jsonOpts, "omitzero") {
+		omit = append(omit, g.zeroCond(typeExpr, varExpr))
+	} else {
+		zero = g.zeroCond(typeExpr, varExpr)
+	}
+	if slices.Contains(jsonOpts, "omitempty") && !slices.Contains(jsonOpts, "format:number") {
+		if cond := g.emptyCond(typeExpr, varExpr); cond != "" {
+			omit = append(omit, cond)
+		}
+	}
+	return zero, omit
+}
+
+// structEmptyCond returns a condition reporting whether all fields of the
+// struct varExpr, including the fields of embedded structs, are omitted, so
+// that it is encoded as {}.
+func (g *generator) structEmptyCond(st *ast.StructType, varExpr string) string {
+	visible := g.dominantFields(st)
+	var zeros, conds []string
+	var walk func(st *ast.StructType, varExpr string)
+	walk = func(st *ast.StructType, varExpr string) {
+		for _, field := range st.Fields.List {
+			jsonTag, jsonOpts := parseTag(field)
+			if jsonTag == "-" {
+				continue
+			}
+			if len(field.Names) == 0 || slices.Contains(jsonOpts, "inline") {
+				ts, ok := field.Type.(*ast.Ident)
+				if !ok || unicode.IsLower(rune(ts.Name[0])) {
+					continue
+				}
+				if embedded, ok := g.types[ts.Name].Type.(*ast.StructType); ok {
+					restore := g.inFile(ts.Name)
+					walk(embedded, varExpr+"."+ts.Name)
+					restore()
+				}
+				continue
+			}
+			for _, name := range field.Names {
+				if !visible[name] {
+					continue
+				}
+				zero, omit := g.omitConds(field.Type, jsonOpts, varExpr+"."+name.Name)
+				if len(omit) == 0 {
+					zeros = append(zeros, zero)
+					continue
+				}
+				conds = append(conds, "("+g.omitCond(field.Type, jsonOpts, varExpr+"."+name.Name)+")")
+			}
+		}
+	}
+	walk(st, varExpr)
+	if len(zeros) > 0 {
+		// Fields only omitted when zero with json.OmitZeroStructFields
+		conds = append(append(zeros, conds...), g.lazyOption("e", "json.OmitZeroStructFields"))
+	}
+	if len(conds) == 0 {
+		return "true"
+	}
+	return strings.Join(conds, " && ")
+}
+
+// emptyCond returns a condition reporting whether varExpr would be encoded as
+// an empty JSON value (null, "", {} or []), or "" if it never would be.
+func (g *generator) emptyCond(typeExpr ast.Expr, varExpr string) string {
+	switch ts := typeExpr.(type) {
+	case *ast.Ident:
+		switch ts.Name {
+		case "string":
+			return varExpr + ` == ""`
+		case "any":
+			return varExpr + " == nil"
+		}
+		if typeSpec, ok := g.types[ts.Name]; ok {
+			defer g.inFile(ts.Name)()
+			return g.emptyCond(typeSpec.Type, varExpr)
+		}
+	case *ast.ArrayType, *ast.MapType:
+		return "len(" + varExpr + ") == 0"
+	case *ast.StarExpr:
+		if cond := g.emptyCond(ts.X, "(*"+varExpr+")"); cond != "" {
+			return fmt.Sprintf("%s == nil || (%s)", varExpr, cond)
+		}
+		return varExpr + " == nil"
+	case *ast.StructType:
+		return g.structEmptyCond(ts, varExpr)
+	}
+	return ""
+}
+
+// zeroCond returns a condition reporting whether varExpr is the zero value of
+// its type.
+func (g *generator) zeroCond(typeExpr ast.Expr, varExpr string) string {
+	switch ts := typeExpr.(type) {
+	case *ast.Ident:
+		switch predeclaredShape(ts.Name) {
+		case "string":
+			return varExpr + ` == ""`
+		case "bool":
+			return "!" + varExpr
+		case "any":
+			return varExpr + " == nil"
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return varExpr + " == 0"
+		}
+		if typeSpec, ok := g.types[ts.Name]; ok {
+			defer g.inFile(ts.Name)()
+			if sel, ok := typeSpec.Type.(*ast.SelectorExpr); ok {
+				varExpr = g.asType(sel, ts.Name, varExpr)
+			}
+			return g.zeroCond(typeSpec.Type, varExpr)
+		}
+	case *ast.SelectorExpr:
+		if X, ok := ts.X.(*ast.Ident); ok {
+			switch g.importPath(X) + "." + ts.Sel.Name {
+			case "time.Time":
+				return varExpr + ".IsZero()"
+			case "encoding/json.Number":
+				return varExpr + ` == ""`
+			case "math/big.Int", "math/big.Float", "math/big.Rat":
+				// Also true for zeros that are not the zero value, e.g. with
+				// a precision set
+				return varExpr + ".Sign() == 0"
+			}
+			if g.cfg.Codecs[g.importPath(X)+"."+ts.Sel.Name] != "" {
+				// Types of codecs may not be comparable
+				g.useImports("reflect")
+				return "reflect.ValueOf(" + varExpr + ").IsZero()"
+			}
+		}
+	case *ast.ArrayType, *ast.MapType, *ast.StarExpr:
+		return varExpr + " == nil"
+	case *ast.StructType:
+		var conds []string
+		for _, field := range ts.Fields.List {
+			for _, name := range field.Names {
+				conds = append(conds, g.zeroCond(field.Type, varExpr+"."+name.Name))
+			}
+			if len(field.Names) == 0 {
+				conds = append(conds, g.zeroCond(field.Type, varExpr+"."+embeddedName(field.Type)))
+			}
+		}
+		// Empty structs are always zero
+		conds = slices.DeleteFunc(conds, func(cond string) bool { return cond == "true" })
+		if len(conds) == 0 {
+			return "true"
+		}
+		return strings.Join(conds, " && ")
+	}
+	fail(typeExpr, "omitzero is not supported for type: %s", exprToString(typeExpr))
+	return ""
+}
+
+// embeddedName returns the field name of an embedded field of type expr.
+func embeddedName(expr ast.Expr) string {
+	switch ts := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(ts.X)
+	case *ast.SelectorExpr:
+		return ts.Sel.Name
+	default:
+		return exprToString(expr)
+	}
+}
+
+func (g *generator) marshalerArray(elemType ast.Expr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler array: %s", varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler array: %s")`, varExpr))
+	}
+	// Nil slices are encoded as [] unless NilSlice or the options say null
+	var nullCond string
+	switch g.cfg.NilSlice {
+	case "null":
+		nullCond = varExpr + " == nil"
+	case "":
+		nullCond = fmt.Sprintf("%s == nil && %s", varExpr, g.lazyOption("e", "json.FormatNilSliceAsNull"))
+	}
+	if nullCond != "" {
+		g.writeLine(fmt.Sprintf("if %s {", nullCond))
+		g.indent()
+		g.writeToken("jsontext.Null")
+		g.unindent()
+		g.writeLine("} else {")
+		g.indent()
+	}
+	g.writeToken("jsontext.BeginArray")
+	elem := g.tmpName("elem")
+	g.indent()
+	g.depth++
+	body := g.capture(func() {
+		g.marshaler(g.typeString(elemType), elemType, elem)
+	})
+	g.depth--
+	g.unindent()
+	if usesIdent(body, elem) {
+		g.writeLine(fmt.Sprintf("for _, %s := range %s {", elem, varExpr))
+	} else {
+		// e.g. elements of type struct{}
+		g.writeLine(fmt.Sprintf("for range %s {", varExpr))
+	}
+	g.body.WriteString(body)
+	g.writeLine("}")
+	g.writeToken("jsontext.EndArray")
+	if nullCond != "" {
+		g.unindent()
+		g.writeLine("}")
+	}
+}
+
+func (g *generator) marshalerMap(keyType ast.Expr, valueType ast.Expr, varExpr string) {
+	if kt, ok := keyType.(*ast.Ident); !ok || kt.Name != "string" {
+		fail(keyType, "JSON does not support non-string map keys")
+	}
+	g.writeToken("jsontext.BeginObject")
+	key, value := g.tmpName("key"), g.tmpName("value")
+	g.indent()
+	g.depth++
+	body := g.capture(func() {
+		g.writeToken(fmt.Sprintf("jsontext.String(%s)", key))
+		g.marshaler(g.typeString(valueType), valueType, value)
+	})
+	g.depth--
+	g.unindent()
+	// Maps are ranged over directly, unless sorted for json.Deterministic
+	sorted := g.capture(func() {
+		g.useImports("maps", "slices")
+		g.writeLine(fmt.Sprintf("for _, %s := range slices.Sorted(maps.Keys(%s)) {", key, varExpr))
+		if usesIdent(body, value) {
+			g.writeLine(fmt.Sprintf("\t%s := %s[%s]", value, varExpr, key))
+		}
+		g.body.WriteString(body)
+		g.writeLine("}")
+	})
+	if g.opts.deterministic {
+		g.body.WriteString(sorted)
+		g.writeToken("jsontext.EndObject")
+		return
+	}
+	g.writeLine(fmt.Sprintf("if len(%s) > 1 && %s {", varExpr, g.lazyOption("e", "json.Deterministic")))
+	g.body.WriteString(indentCode(sorted))
+	g.writeLine("} else {")
+	g.indent()
+	if usesIdent(body, value) {
+		g.writeLine(fmt.Sprintf("for %s, %s := range %s {", key, value, varExpr))
+	} else {
+		g.writeLine(fmt.Sprintf("for %s := range %s {", key, varExpr))
+	}
+	g.body.WriteString(indentCode(body))
+	g.writeLine("}")
+	g.unindent()
+	g.writeLine("}")
+	g.writeToken("jsontext.EndObject")
+}
+
+// usesIdent reports whether code refers to the identifier name.
+func usesIdent(code string, name string) bool {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(code)
+}
+
+func (g *generator) marshalerPointer(ts *ast.StarExpr, varExpr string) {
+	if g.cfg.Debug {
+		log.Printf("- marshaler pointer: %s", varExpr)
+		g.writeLine(fmt.Sprintf(`log.Println("- marshaler pointer: %s")`, varExpr))
+	}
+	g.writeLine(fmt.Sprintf("if %s == nil {", varExpr))
+	g.indent()
+	g.writeToken("jsontext.Null")
+	g.unindent()
+	g.writeLine("} else {")
+	g.indent()
+	g.marshaler(g.typeString(ts.X), ts.X, fmt.Sprintf("(*%s)", varExpr))
+	g.unindent()
+	g.writeLine("}")
+}
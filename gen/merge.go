@@ -0,0 +1,105 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// Decoding follows the merge semantics of json/v2: objects are merged into
+// existing structs, maps and the maps held by any values, pointers are reused,
+// and any other value is replaced, with JSON null storing the zero value.
+// With jsonv1.MergeWithLegacySemantics, null preserves scalars and structs,
+// slice elements are merged and map values are replaced, like in v1.
+
+// unmarshalerNull emits the code decoding a JSON null into varExpr of type
+// typeExpr, or the code written by decode for any other value.
+func (g *generator) unmarshalerNull(typeExpr ast.Expr, varExpr string, decode func()) {
+	zero, preserve := g.nullValue(typeExpr)
+	if zero == "" && !preserve {
+		// Decoded by decode
+		decode()
+		return
+	}
+	g.writeMultiline(`
+		if d.PeekKind() == 'n' {
+			if _, err = d.ReadToken(); err != nil {
+				return err
+			}
+	`)
+	g.indent()
+	switch {
+	case zero == "":
+		g.writeLine("// Unchanged, as by its UnmarshalJSON method")
+	case preserve:
+		g.writeLine(fmt.Sprintf("if !%s {", g.useOption("jsonv1.MergeWithLegacySemantics")))
+		g.writeLine(fmt.Sprintf("\t%s = %s", varExpr, zero))
+		g.writeLine("}")
+	default:
+		g.writeLine(fmt.Sprintf("%s = %s", varExpr, zero))
+	}
+	g.unindent()
+	g.writeLine("} else {")
+	g.indent()
+	decode()
+	g.unindent()
+	g.writeLine("}")
+}
+
+// nullValue returns the zero value stored into a value of type typeExpr when
+// decoding a JSON null, and whether legacy semantics preserve the value
+// instead. The zero value is "" for pointers, any values and types decoded
+// by json/v2 as codecs, which handle null themselves, and for big numbers,
+// which null leaves unchanged.
+func (g *generator) nullValue(typeExpr ast.Expr) (zero string, preserve bool) {
+	switch ts := typeExpr.(type) {
+	case *ast.Ident:
+		switch predeclaredShape(ts.Name) {
+		case "string":
+			return `""`, true
+		case "bool":
+			return "false", true
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return "0", true
+		case "any":
+			return "", false
+		}
+		if typeSpec, ok := g.types[ts.Name]; ok {
+			defer g.inFile(ts.Name)()
+			zero, preserve := g.nullValue(typeSpec.Type)
+			switch {
+			case strings.HasSuffix(zero, "{}"):
+				// Composite literal of the named type
+				zero = ts.Name + "{}"
+			case strings.HasPrefix(zero, "*new("):
+				zero = "*new(" + ts.Name + ")"
+			}
+			return zero, preserve
+		}
+	case *ast.SelectorExpr:
+		if X, ok := ts.X.(*ast.Ident); ok {
+			path := g.importPath(X) + "." + ts.Sel.Name
+			switch path {
+			case "time.Time":
+				g.useTypeImports(ts)
+				return g.typeString(ts) + "{}", true
+			case "encoding/json.Number":
+				return `""`, true
+			case "math/big.Int", "math/big.Float", "math/big.Rat":
+				return "", true
+			}
+			if g.cfg.Codecs[path] == "text" {
+				g.useTypeImports(ts)
+				return "*new(" + g.typeString(ts) + ")", true
+			}
+		}
+	case *ast.StructType:
+		g.useTypeImports(ts)
+		return g.typeString(ts) + "{}", true
+	case *ast.ArrayType, *ast.MapType:
+		return "nil", false
+	}
+	return "", false
+}
@@ -0,0 +1,150 @@
+package gen
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// useOption returns the name of the local variable holding the boolean
+// option setter (e.g. "json.StringifyNumbers") of the Decoder or Encoder
+// passed to the method being generated. Options are only read when the
+// generated code depends on them, and every branch they guard keeps the code
+// for the default options as its fast path. Options only needed on rare paths
+// are read there instead, with lazyOption.
+func (g *generator) useOption(setter string) string {
+	g.options[setter] = true
+	name := setter[strings.IndexByte(setter, '.')+1:]
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// declareOptions declares the variables of the options used by the method
+// being generated, reading them from coder (the Decoder or Encoder).
+func (g *generator) declareOptions(coder string) {
+	if len(g.options) == 0 {
+		return
+	}
+	g.writeLine(fmt.Sprintf("opts := %s.Options()", coder))
+	for _, setter := range slices.Sorted(maps.Keys(g.options)) {
+		if strings.HasPrefix(setter, "jsonv1.") {
+			// Legacy options are declared by encoding/json
+			g.useImports("encoding/json")
+		}
+		g.writeLine(fmt.Sprintf("%s, _ := json.GetOption(opts, %s)", g.useOption(setter), setter))
+	}
+	clear(g.options)
+}
+
+// foldName folds name the way json/v2 matches names with
+// json.MatchCaseInsensitiveNames: case-insensitively, ignoring '_' and '-'.
+// With ignoreCase, '_' and '-' are significant like in encoding/json v1, so
+// that names match as with strings.EqualFold. It matches the helper emitted
+// by useFoldName.
+func foldName(name string, ignoreCase bool) string {
+	var b []byte
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			if (r == '_' || r == '-') && !ignoreCase {
+				continue
+			}
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b = append(b, byte(r))
+			continue
+		}
+		// Use the smallest rune of the fold set
+		for {
+			r2 := unicode.SimpleFold(r)
+			if r2 <= r {
+				r = r2
+				break
+			}
+			r = r2
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b)
+}
+
+// useFoldName emits a helper folding JSON member names like foldName. It
+// returns the name of the helper.
+func (g *generator) useFoldName() string {
+	return g.useHelper("foldName", func(h *generator, name string) {
+		h.useImports("unicode", "unicode/utf8")
+		doc, skip := "case-insensitively, ignoring '_' and '-'", `
+						if r == '_' || r == '-' {
+							continue
+						}`
+		if g.cfg.IgnoreCase {
+			doc, skip = "case-insensitively like strings.EqualFold", ""
+		}
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s folds name the way JSON member names are matched
+			// %[2]s.
+			func %[1]s(name string) string {
+				b := make([]byte, 0, len(name))
+				for _, r := range name {
+					if r < utf8.RuneSelf {%[3]s
+						if 'a' <= r && r <= 'z' {
+							r -= 'a' - 'A'
+						}
+						b = append(b, byte(r))
+						continue
+					}
+					for {
+						r2 := unicode.SimpleFold(r)
+						if r2 <= r {
+							r = r2
+							break
+						}
+						r = r2
+					}
+					b = utf8.AppendRune(b, r)
+				}
+				return string(b)
+			}
+		`, name, doc, skip))
+	})
+}
+
+// useIsNumber emits a helper reporting whether a string holds a valid JSON
+// number. It returns the name of the helper.
+func (g *generator) useIsNumber() string {
+	return g.useHelper("isNumber", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "strings")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s reports whether s is a valid JSON number.
+			func %[1]s(s string) bool {
+				return strings.TrimSpace(s) == s && jsontext.Value(s).Kind() == '0' && jsontext.Value(s).IsValid()
+			}
+		`, name))
+	})
+}
+
+// lazyOption returns an expression reading the boolean option setter from
+// coder (the Decoder or Encoder) when evaluated, for the options only needed
+// on rare paths, e.g. once a field is zero or a member is unknown, so that
+// the other paths do not pay for reading them.
+func (g *generator) lazyOption(coder string, setter string) string {
+	return fmt.Sprintf("%s(%s.Options(), %s)", g.useGetOption(), coder, setter)
+}
+
+// useGetOption emits a helper reading a boolean option. It returns the name
+// of the helper.
+func (g *generator) useGetOption() string {
+	return g.useHelper("getOption", func(h *generator, name string) {
+		h.useImports("encoding/json/v2")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s reports whether the boolean option of setter is set in
+			// opts.
+			func %[1]s(opts json.Options, setter func(bool) json.Options) bool {
+				v, _ := json.GetOption(opts, setter)
+				return v
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Package is a Go package loaded for generation.
+type Package struct {
+	Name  string                   // package name
+	Dir   string                   // directory of the package files
+	Fset  *token.FileSet           // file set of the package files
+	Files map[string]*ast.File     // map of package types to declaring files
+	Types map[string]*ast.TypeSpec // map of package types
+}
+
+// LoadPackages loads the packages matching patterns (as accepted by go build)
+// in the directory dir with the build tags tags, leaving out test files and
+// the files excluded by build constraints. Errors loading or parsing the
+// packages are all returned together as Diagnostics.
+func LoadPackages(ctx context.Context, dir string, patterns []string, tags string) ([]*Package, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax,
+		Dir:     dir,
+		Fset:    token.NewFileSet(),
+	}
+	if tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + tags}
+	}
+	loaded, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var diags Diagnostics
+	pkgs := make([]*Package, 0, len(loaded))
+	for _, lp := range loaded {
+		for _, err := range lp.Errors {
+			diags = append(diags, packageDiagnostic(err))
+		}
+		if len(lp.GoFiles) == 0 {
+			continue
+		}
+		pkgs = append(pkgs, newPackage(cfg.Fset, lp))
+	}
+	if len(diags) > 0 {
+		return nil, diags
+	}
+	return pkgs, nil
+}
+
+func newPackage(fset *token.FileSet, lp *packages.Package) *Package {
+	p := &Package{
+		Name:  lp.Name,
+		Dir:   filepath.Dir(lp.GoFiles[0]),
+		Fset:  fset,
+		Files: make(map[string]*ast.File),
+		Types: make(map[string]*ast.TypeSpec),
+	}
+	for _, node := range lp.Syntax {
+		// Inspect declarations
+		for _, decl := range node.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			if genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if ts.Assign != token.NoPos {
+					// TODO: handle type aliases
+					continue
+				}
+				if ts.Doc == nil && !genDecl.Lparen.IsValid() {
+					// Doc comment of a single type declaration
+					ts.Doc = genDecl.Doc
+				}
+				p.Files[ts.Name.Name] = node
+				p.Types[ts.Name.Name] = ts
+			}
+		}
+	}
+	return p
+}
+
+// packageDiagnostic returns the diagnostic of an error loading a package,
+// located by its "file:line:col" position when it has one.
+func packageDiagnostic(err packages.Error) Diagnostic {
+	d := Diagnostic{Message: err.Msg}
+	file, line, col := err.Pos, 0, 0
+	if i := strings.LastIndexByte(file, ':'); i >= 0 {
+		if n, err := strconv.Atoi(file[i+1:]); err == nil {
+			file, col = file[:i], n
+		}
+	}
+	if i := strings.LastIndexByte(file, ':'); i >= 0 {
+		if n, err := strconv.Atoi(file[i+1:]); err == nil {
+			file, line = file[:i], n
+		}
+	}
+	if line == 0 {
+		line, col = col, 0
+	}
+	if line > 0 {
+		d.Pos = token.Position{Filename: file, Line: line, Column: col}
+	} else if err.Pos != "" && err.Pos != "-" {
+		d.Message = err.Pos + ": " + err.Msg
+	}
+	return d
+}
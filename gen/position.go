@@ -0,0 +1,37 @@
+package gen
+
+import "fmt"
+
+// usePositionError emits a helper prefixing the errors of decoding an input
+// held in memory with the line and column of the offending byte, which the
+// byte offsets of json/v2 errors leave for the reader to count. It returns
+// the name of the helper.
+func (g *generator) usePositionError() string {
+	return g.useHelper("positionError", func(h *generator, name string) {
+		h.useImports("bytes", "encoding/json/jsontext", "encoding/json/v2", "errors", "fmt")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s prefixes err, returned when decoding b, with the line and
+			// column of the byte it occurred at, both starting at 1. Columns
+			// count bytes.
+			func %[1]s(b []byte, err error) error {
+				var offset int64
+				var serr *json.SemanticError
+				var synErr *jsontext.SyntacticError
+				switch {
+				case errors.As(err, &synErr):
+					offset = synErr.ByteOffset
+				case errors.As(err, &serr):
+					offset = serr.ByteOffset
+				default:
+					return err
+				}
+				if offset < 0 || offset > int64(len(b)) {
+					return err
+				}
+				line := 1 + bytes.Count(b[:offset], []byte("\n"))
+				column := int(offset) - bytes.LastIndexByte(b[:offset], '\n')
+				return fmt.Errorf("line %%d, column %%d: %%w", line, column, err)
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,177 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// presence tracks which members of a JSON object decoded into a struct have
+// been seen, in a bitset indexed by the position of the member in the struct.
+type presence struct {
+	name       string         // name of the bitset variable
+	index      map[string]int // map of JSON names to bit indexes
+	duplicates bool           // whether duplicates are checked for
+	forced     bool           // whether duplicates are rejected regardless of options
+}
+
+func newPresence(members []jsonMember) *presence {
+	p := &presence{name: "seen", index: make(map[string]int)}
+	for i, m := range members {
+		p.index[m.name] = i
+	}
+	return p
+}
+
+// decl returns the declaration of the bitset variable.
+func (p *presence) decl() string {
+	if n := len(p.index); n > 64 {
+		return fmt.Sprintf("var %s [%d]uint64", p.name, (n+63)/64)
+	}
+	return fmt.Sprintf("var %s uint64", p.name)
+}
+
+// word returns the bitset word holding the bit of member name, and the index
+// of the bit in the word.
+func (p *presence) word(name string) (string, int) {
+	i := p.index[name]
+	if len(p.index) > 64 {
+		return fmt.Sprintf("%s[%d]", p.name, i/64), i % 64
+	}
+	return p.name, i
+}
+
+// has returns a condition reporting whether member name has been seen.
+func (p *presence) has(name string) string {
+	word, bit := p.word(name)
+	return fmt.Sprintf("%s&(1<<%d) != 0", word, bit)
+}
+
+// missing returns a condition reporting whether member name has not been
+// seen.
+func (p *presence) missing(name string) string {
+	word, bit := p.word(name)
+	return fmt.Sprintf("%s&(1<<%d) == 0", word, bit)
+}
+
+// set returns a statement marking member name as seen.
+func (p *presence) set(name string) string {
+	word, bit := p.word(name)
+	return fmt.Sprintf("%s |= 1 << %d", word, bit)
+}
+
+// unmarshalerPresence marks the member name just read as seen, rejecting it
+// if it was already seen.
+func (g *generator) unmarshalerPresence(p *presence, name string) {
+	if !p.duplicates {
+		g.writeLine(p.set(name))
+		return
+	}
+	cond := p.has(name)
+	if !p.forced {
+		cond += " && !" + g.lazyOption("d", "jsontext.AllowDuplicateNames")
+	}
+	g.writeMultiline(fmt.Sprintf(`
+		if %s {
+			return %s(d, t)
+		}
+		%s
+	`, cond, g.useDuplicateNameError(), p.set(name)))
+}
+
+// unmarshalerRequired reports the required members missing from the object
+// just decoded into varExpr.
+func (g *generator) unmarshalerRequired(p *presence, members []jsonMember, varExpr string) {
+	masks := make(map[string]uint64)
+	var words []string
+	for _, m := range members {
+		if !m.required {
+			continue
+		}
+		word, bit := p.word(m.name)
+		if _, ok := masks[word]; !ok {
+			words = append(words, word)
+		}
+		masks[word] |= 1 << bit
+	}
+	if len(words) == 0 {
+		return
+	}
+	var conds []string
+	for _, word := range words {
+		conds = append(conds, fmt.Sprintf("%s&%#x != %#x", word, masks[word], masks[word]))
+	}
+	g.writeLine(fmt.Sprintf("if %s {", strings.Join(conds, " || ")))
+	g.indent()
+	g.writeLine("var missing []string")
+	for _, m := range members {
+		if m.required {
+			g.writeLine(fmt.Sprintf("if %s {", p.missing(m.name)))
+			g.writeLine(fmt.Sprintf("\tmissing = append(missing, %q)", m.name))
+			g.writeLine("}")
+		}
+	}
+	g.useImports("reflect")
+	g.writeLine(fmt.Sprintf("return %s(d, reflect.TypeOf(&%s).Elem(), missing)", g.useMissingMembersError(), varExpr))
+	g.unindent()
+	g.writeLine("}")
+}
+
+// hasDirective reports whether the declaration of the named type typeName is
+// annotated with a //gogenjson:name comment.
+func (g *generator) hasDirective(typeName string, name string) bool {
+	typeSpec, ok := g.types[typeName]
+	if !ok {
+		return false
+	}
+	_, ok = directive(typeSpec.Doc, name)
+	return ok
+}
+
+// useMissingMembersError emits a helper building the error for required
+// members missing from an object. It returns the name of the helper.
+func (g *generator) useMissingMembersError() string {
+	return g.useHelper("missingMembersError", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext", "encoding/json/v2", "errors", "reflect", "strconv", "strings")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the error for the required members names
+			// missing from the object of type goType just read from d.
+			func %[1]s(d *jsontext.Decoder, goType reflect.Type, names []string) error {
+				quoted := make([]string, len(names))
+				for i, name := range names {
+					quoted[i] = strconv.Quote(name)
+				}
+				msg := "missing required member "
+				if len(names) > 1 {
+					msg = "missing required members "
+				}
+				return &json.SemanticError{
+					ByteOffset:  d.InputOffset() - int64(len("}")),
+					JSONPointer: d.StackPointer(),
+					JSONKind:    '{',
+					GoType:      goType,
+					Err:         errors.New(msg + strings.Join(quoted, ", ")),
+				}
+			}
+		`, name))
+	})
+}
+
+// useDuplicateNameError emits a helper building the error json/v2 reports
+// for a duplicate member name. It returns the name of the helper.
+func (g *generator) useDuplicateNameError() string {
+	return g.useHelper("duplicateNameError", func(h *generator, name string) {
+		h.useImports("encoding/json/jsontext")
+		h.writeMultiline(fmt.Sprintf(`
+			// %[1]s returns the error for the member name t just read from d
+			// that duplicates a previous member of the object.
+			func %[1]s(d *jsontext.Decoder, t jsontext.Token) error {
+				b, _ := jsontext.AppendQuote(nil, t.String())
+				return &jsontext.SyntacticError{
+					ByteOffset:  d.InputOffset() - int64(len(b)),
+					JSONPointer: d.StackPointer(),
+					Err:         jsontext.ErrDuplicateName,
+				}
+			}
+		`, name))
+	})
+}
@@ -0,0 +1,351 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// typeGen generates random nested type expressions.
+type typeGen struct {
+	r     *rand.Rand
+	named []string // named struct types declared so far
+	decls strings.Builder
+}
+
+func (tg *typeGen) expr(depth int) string {
+	leaves := []string{"string", "int", "int8", "uint16", "int64", "float32", "float64", "bool", "time.Time", "any"}
+	leaves = append(leaves, tg.named...)
+	if depth <= 0 || tg.r.IntN(4) == 0 {
+		return leaves[tg.r.IntN(len(leaves))]
+	}
+	switch tg.r.IntN(4) {
+	case 0:
+		return "[]" + tg.expr(depth-1)
+	case 1:
+		return "*" + tg.expr(depth-1)
+	case 2:
+		return "map[string]" + tg.expr(depth-1)
+	default:
+		return tg.structExpr(depth - 1)
+	}
+}
+
+func (tg *typeGen) structExpr(depth int) string {
+	var fields []string
+	for i := range tg.r.IntN(4) {
+		fields = append(fields, fmt.Sprintf("F%d %s `json:\"f%d\"`", i, tg.expr(depth), i))
+	}
+	if len(fields) == 0 {
+		return "struct{}"
+	}
+	return "struct{ " + strings.Join(fields, "; ") + " }"
+}
+
+// declare declares a named struct type with random fields.
+func (tg *typeGen) declare(name string, depth int) {
+	fmt.Fprintf(&tg.decls, "type %s %s\n\n", name, tg.structExpr(depth))
+	tg.named = append(tg.named, name)
+}
+
+// roundTripTest checks one generated type against json/v2. Type W%[1]d has the
+// same underlying type as T%[1]d, but no generated methods.
+const roundTripTest = `
+type W%[1]d T%[1]d
+
+func TestT%[1]d(t *testing.T) {
+	r := rand.New(rand.NewPCG(%[1]d, 0))
+	for range 20 {
+		var v T%[1]d
+		fill(r, reflect.ValueOf(&v).Elem(), 0)
+		check(t, &v, (*W%[1]d)(&v), func(seed uint64) (any, any, func() any) {
+			var gen T%[1]d
+			var ref W%[1]d
+			if seed > 0 {
+				fill(rand.New(rand.NewPCG(seed, 0)), reflect.ValueOf(&gen).Elem(), 0)
+				fill(rand.New(rand.NewPCG(seed, 0)), reflect.ValueOf(&ref).Elem(), 0)
+			}
+			return &gen, &ref, func() any { return (*W%[1]d)(&gen) }
+		})
+	}
+}
+`
+
+const roundTripHelpers = `package roundtrip
+
+import (
+	"bytes"
+	jsonv1 "encoding/json"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// check compares marshaling gen (with generated methods) and ref (without)
+// and unmarshaling ref's JSON into values of both types made by fresh, which
+// are pre-populated with random values for a non-zero seed.
+func check(t *testing.T, gen, ref any, fresh func(seed uint64) (any, any, func() any)) {
+	t.Helper()
+	genJSON, err := json.Marshal(gen)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	refJSON, err := json.Marshal(ref)
+	if err != nil {
+		t.Fatalf("reference marshal: %v", err)
+	}
+	if !equalJSON(genJSON, refJSON) {
+		t.Fatalf("marshal: got %s, want %s", genJSON, refJSON)
+	}
+	checkCorpus(t, refJSON, fresh)
+}
+
+// checkCorpus checks that unmarshaling into the generated type agrees with
+// unmarshaling into the reference type, on errors and on results, for every
+// truncation of in (also with an unknown member in front), in followed by
+// trailing garbage, and in with any value replaced by null or one of the
+// wrong kind. In and its replacements are also decoded onto pre-populated
+// values, with and without legacy merge semantics. Except for trailing
+// garbage, which only json.Unmarshal detects, UnmarshalJSONFrom is also
+// checked on its own.
+func checkCorpus(t *testing.T, in []byte, fresh func(seed uint64) (any, any, func() any)) {
+	t.Helper()
+	agree := func(b []byte, seed uint64, opts ...json.Options) {
+		t.Helper()
+		gen, ref, genAsRef := fresh(seed)
+		err, refErr := json.Unmarshal(b, gen, opts...), json.Unmarshal(b, ref, opts...)
+		if (err == nil) != (refErr == nil) {
+			t.Fatalf("unmarshal %s (seed %d, options %v): got error %v, want %v", b, seed, opts, err, refErr)
+		}
+		if err != nil {
+			return
+		}
+		genJSON, _ := json.Marshal(genAsRef())
+		refJSON, _ := json.Marshal(ref)
+		if !equalJSON(genJSON, refJSON) {
+			t.Fatalf("unmarshal %s (seed %d, options %v): got %s, want %s", b, seed, opts, genJSON, refJSON)
+		}
+	}
+	var v any
+	if err := json.Unmarshal(in, &v); err != nil {
+		t.Fatalf("unmarshal %s: %v", in, err)
+	}
+	values := [][]byte{in}
+	for _, m := range mutate(v) {
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("marshal %v: %v", m, err)
+		}
+		values = append(values, b)
+	}
+	for i, b := range values {
+		agree(b, 0)
+		agree(b, uint64(i+1))
+		agree(b, uint64(i+1), jsonv1.MergeWithLegacySemantics(true))
+	}
+	corpus := slices.Clone(values)
+	unknown := append([]byte("{\"unknown\":[{\"a\":1}],"), in[1:]...)
+	for i := range in {
+		corpus = append(corpus, in[:i])
+	}
+	for i := range unknown {
+		corpus = append(corpus, unknown[:i])
+	}
+	garbage := [][]byte{append(slices.Clip(in), " x"...), append(slices.Clip(in), "{}"...)}
+	for _, b := range append(corpus[len(values):], garbage...) {
+		agree(b, 0)
+	}
+	for _, b := range corpus {
+		gen, ref, _ := fresh(0)
+		refErr := json.Unmarshal(b, ref)
+		d := jsontext.NewDecoder(bytes.NewReader(b))
+		if err := gen.(json.UnmarshalerFrom).UnmarshalJSONFrom(d); (err == nil) != (refErr == nil) {
+			t.Fatalf("UnmarshalJSONFrom %s: got error %v, want %v", b, err, refErr)
+		}
+	}
+}
+
+// wrongKinds are the values that mutate substitutes for every JSON value.
+var wrongKinds = []any{nil, true, "x", 1.5, -1.0, map[string]any{}, []any{}}
+
+// mutate returns copies of v with v itself, or any value nested in it,
+// replaced by each of wrongKinds.
+func mutate(v any) []any {
+	out := slices.Clone(wrongKinds)
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			for _, m := range mutate(value) {
+				c := maps.Clone(v)
+				c[key] = m
+				out = append(out, c)
+			}
+		}
+	case []any:
+		for i, elem := range v {
+			for _, m := range mutate(elem) {
+				c := slices.Clone(v)
+				c[i] = m
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+func equalJSON(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// fill sets v to a small random value.
+func fill(r *rand.Rand, v reflect.Value, depth int) {
+	if v.Type() == reflect.TypeFor[time.Time]() {
+		v.Set(reflect.ValueOf(time.Unix(r.Int64N(1e10), r.Int64N(1e9)).UTC()))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		const chars = "ab \"\\/\né <&"
+		s := []rune{}
+		for range r.IntN(5) {
+			s = append(s, []rune(chars)[r.IntN(len([]rune(chars)))])
+		}
+		v.SetString(string(s))
+	case reflect.Int, reflect.Int8, reflect.Int64:
+		v.SetInt(r.Int64N(256) - 128)
+	case reflect.Uint16:
+		v.SetUint(r.Uint64N(1 << 16))
+	case reflect.Float32:
+		v.SetFloat(float64(float32(r.NormFloat64() * 1000)))
+	case reflect.Float64:
+		v.SetFloat(r.NormFloat64() * 1e6)
+	case reflect.Bool:
+		v.SetBool(r.IntN(2) == 0)
+	case reflect.Interface:
+		switch r.IntN(4) {
+		case 1:
+			v.Set(reflect.ValueOf(r.NormFloat64()))
+		case 2:
+			v.Set(reflect.ValueOf(map[string]any{"a": r.NormFloat64(), "b": map[string]any{"c": "d"}}))
+		case 3:
+			v.Set(reflect.ValueOf([]any{"a", true}))
+		}
+	case reflect.Pointer:
+		if r.IntN(3) > 0 {
+			v.Set(reflect.New(v.Type().Elem()))
+			fill(r, v.Elem(), depth+1)
+		}
+	case reflect.Slice:
+		if n := r.IntN(4); n > 0 {
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+			for i := range n {
+				fill(r, v.Index(i), depth+1)
+			}
+		}
+	case reflect.Map:
+		if n := r.IntN(4); n > 0 {
+			v.Set(reflect.MakeMap(v.Type()))
+			for i := range n {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				fill(r, elem, depth+1)
+				v.SetMapIndex(reflect.ValueOf(string(rune('a'+i))), elem)
+			}
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			fill(r, v.Field(i), depth+1)
+		}
+	}
+}
+`
+
+// TestRoundTrip generates random nested types, generates their methods and
+// verifies that they agree with json/v2.
+func TestRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping round-trip test in short mode")
+	}
+	const n = 30
+	dir := t.TempDir()
+	run := func(dir string, name string, args ...string) {
+		t.Helper()
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOEXPERIMENT=jsonv2")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s %s: %v\n%s", name, strings.Join(args, " "), err, out)
+		}
+	}
+	generate := func(cfg Config) {
+		t.Helper()
+		cfg.Dir = filepath.Join(dir, "roundtrip")
+		files, err := Generate(t.Context(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for path, content := range files {
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	pkg := filepath.Join(dir, "roundtrip")
+	if err := os.Mkdir(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tg := &typeGen{r: rand.New(rand.NewPCG(1, 2))}
+	for i := range 5 {
+		tg.declare(fmt.Sprintf("N%d", i), 2)
+	}
+	var tests strings.Builder
+	tests.WriteString(roundTripHelpers)
+	for i := range n {
+		fmt.Fprintf(&tg.decls, "type T%d %s\n\n", i, tg.structExpr(4))
+		fmt.Fprintf(&tests, roundTripTest, i)
+	}
+	files := map[string]string{
+		"go.mod":            "module roundtrip\n\ngo 1.27\n",
+		"types.go":          "package roundtrip\n\nimport \"time\"\n\nvar _ time.Time\n\n" + tg.decls.String(),
+		"roundtrip_test.go": tests.String(),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(pkg, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var shared []string
+	for i := range n {
+		switch i % 3 {
+		case 0:
+			// Unlimited limits must not change decoding
+			generate(Config{Types: []string{fmt.Sprintf("T%d", i)}, Options: Options{Limits: true}})
+		case 1:
+			shared = append(shared, fmt.Sprintf("T%d", i))
+		default:
+			generate(Config{Types: []string{fmt.Sprintf("T%d", i)}, Options: Options{Fingerprint: true}})
+		}
+	}
+	generate(Config{Types: shared, Output: "shared_gen_json.go"})
+	generated, err := filepath.Glob(filepath.Join(pkg, "*_gen_json.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("gofmt", append([]string{"-l"}, generated...)...).CombinedOutput(); err != nil || len(out) > 0 {
+		t.Errorf("gofmt -l: %v\n%s", err, out)
+	}
+	run(pkg, "go", "test", ".")
+}
@@ -0,0 +1,15 @@
+package all
+
+type Second struct {
+	Name string `json:"name"`
+}
+
+type First struct {
+	Second Second `json:"second"`
+}
+
+type unexported struct{}
+
+type Named string
+
+type Values map[string]int
@@ -0,0 +1,4 @@
+package broken
+
+type Broken struct {
+	Name string `json:"name"`
@@ -0,0 +1,17 @@
+package diagnostics
+
+type Warned struct {
+	Count int `json:"count,nocase"`
+}
+
+type Malformed struct {
+	Name string `json:"name`
+}
+
+type Keyed struct {
+	Index map[int]string
+}
+
+type Quoted struct {
+	Count int `json:"count,string"`
+}
@@ -0,0 +1,8 @@
+package fallback
+
+type Amounts struct {
+	Total string `json:"total,format:number"`
+	Lines []struct {
+		Amount string `json:"amount,format:number"`
+	} `json:"lines"`
+}
@@ -0,0 +1,15 @@
+package imports
+
+import (
+	json "encoding/json"
+	bigmath "math/big"
+	stdtime "time"
+)
+
+type Imports struct {
+	Number json.Number     `json:"number"`
+	At     stdtime.Time    `json:"at"`
+	Big    *bigmath.Int    `json:"big"`
+	Times  []stdtime.Time  `json:"times"`
+	Index  map[string]bool `json:"index"`
+}
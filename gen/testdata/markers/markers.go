@@ -0,0 +1,33 @@
+package markers
+
+//json:generate
+type Plain struct{}
+
+// Strict is marked after its doc comment.
+//
+//gogenjson:codec strict marshal-only=false deterministic
+type Strict struct{}
+
+//json:generate collect=false
+type NoCollect struct{}
+
+//json:generator
+type Unmarked struct{}
+
+//json:generate frobnicate
+type Unknown struct{}
+
+//json:generate strict=maybe
+type Invalid struct{}
+
+// Spaced is marked with the directive gofmt makes of //go-gen-json:codec.
+//
+// go-gen-json:codec deterministic
+type Spaced struct{}
+
+// Directed has directives in both of the forms gofmt keeps.
+//
+// go-gen-json:rejectduplicates
+//
+//gogenjson:rejectunknown
+type Directed struct{}
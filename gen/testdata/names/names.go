@@ -0,0 +1,16 @@
+package names
+
+type Dup struct {
+	A string `json:"a"`
+	B string `json:"a"`
+}
+
+type Inner struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type Outer struct {
+	Inner
+	Name string `json:"name"`
+}
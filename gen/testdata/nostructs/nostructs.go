@@ -0,0 +1,5 @@
+package nostructs
+
+type unexported struct{}
+
+type Named string
@@ -0,0 +1,5 @@
+//go:build special
+
+package tags
+
+type Special struct{}
@@ -0,0 +1,3 @@
+package tags
+
+type InTest struct{}
@@ -0,0 +1,30 @@
+package unsupported
+
+import (
+	"sync"
+	"unsafe"
+)
+
+type Unsupported struct {
+	Chan    chan int
+	Func    func()
+	Complex complex128
+	Pointer unsafe.Pointer
+	Nested  []*Nested
+	Ignored chan int `json:"-"`
+	Valid   string
+	Case    string `json:"case,case:upper"`
+	Byte    byte
+	Rune    rune
+	Bytes   []byte
+	mu      sync.Mutex
+	done    chan struct{}
+}
+
+type Nested struct {
+	Values map[string]Inner
+}
+
+type Inner struct {
+	Baz complex64
+}
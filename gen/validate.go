@@ -0,0 +1,250 @@
+package gen
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ignoreHint suggests excluding an unsupported field from JSON.
+const ignoreHint = "add `json:\"-\"` to ignore the field"
+
+// Validate walks the whole type graph of typeExpr before any code is
+// generated, and returns a problem for every field that go-gen-json cannot
+// encode or decode, located at the field and with a message of the form
+// "path: message". Fields ignored with `json:"-"` are not walked.
+func (g *generator) Validate(typeName string, typeExpr ast.Expr) Diagnostics {
+	v := &validator{
+		g:        g,
+		visiting: map[string]bool{typeName: true},
+	}
+	if _, ok := typeExpr.(*ast.StarExpr); ok {
+		v.report(typeExpr.Pos(), typeName, "methods cannot be declared on pointer type %s (generate for the element type instead)", typeName)
+		return v.problems
+	}
+	v.walk(typeExpr, typeName)
+	return v.problems
+}
+
+type validator struct {
+	g        *generator
+	visiting map[string]bool // named types on the current path
+	problems Diagnostics
+}
+
+func (v *validator) report(pos token.Pos, path string, format string, args ...any) {
+	v.problems = append(v.problems, Diagnostic{Pos: v.g.fset.Position(pos), Message: path + ": " + fmt.Sprintf(format, args...)})
+}
+
+// warn reports a problem that does not prevent generation.
+func (v *validator) warn(pos token.Pos, path string, format string, args ...any) {
+	v.problems = append(v.problems, Diagnostic{Pos: v.g.fset.Position(pos), Severity: Warning, Message: path + ": " + fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) walk(expr ast.Expr, path string) {
+	switch ts := expr.(type) {
+	case *ast.Ident:
+		switch predeclaredShape(ts.Name) {
+		case "string", "bool", "any",
+			"int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return
+		case "complex64", "complex128":
+			v.report(ts.Pos(), path, "unsupported type %s: JSON has no complex numbers (%s, or store the real and imaginary parts in float64 fields)", ts.Name, ignoreHint)
+			return
+		case "uintptr":
+			v.report(ts.Pos(), path, "unsupported type uintptr (%s, or use uint64)", ignoreHint)
+			return
+		}
+		typeSpec, ok := v.g.types[ts.Name]
+		if !ok {
+			v.report(ts.Pos(), path, "unrecognized type %s (%s)", ts.Name, ignoreHint)
+			return
+		}
+		if v.visiting[ts.Name] {
+			v.report(ts.Pos(), path, "recursive type %s is not supported (%s)", ts.Name, ignoreHint)
+			return
+		}
+		v.visiting[ts.Name] = true
+		defer delete(v.visiting, ts.Name)
+		defer v.g.inFile(ts.Name)()
+		v.walk(typeSpec.Type, path)
+	case *ast.SelectorExpr:
+		X, ok := ts.X.(*ast.Ident)
+		if !ok {
+			v.report(ts.Pos(), path, "unsupported type %s (%s)", exprToString(ts), ignoreHint)
+			return
+		}
+		importPath, ok := v.g.lookupImport(X.Name)
+		if !ok {
+			v.report(ts.Pos(), path, "unresolved package %s", X.Name)
+			return
+		}
+		switch name := importPath + "." + ts.Sel.Name; {
+		case v.g.cfg.Codecs[name] != "":
+		case name == "time.Time", name == "encoding/json.Number", name == "math/big.Int", name == "math/big.Float", name == "math/big.Rat":
+		case name == "unsafe.Pointer":
+			v.report(ts.Pos(), path, "unsupported type unsafe.Pointer (%s)", ignoreHint)
+		default:
+			v.report(ts.Pos(), path, "unsupported external type %s (%s, or map %s to a codec)", exprToString(ts), ignoreHint, name)
+		}
+	case *ast.StarExpr:
+		v.walk(ts.X, path)
+	case *ast.ArrayType:
+		if ts.Len != nil {
+			v.report(ts.Pos(), path, "unsupported array type %s (%s, or use a slice)", exprToString(ts), ignoreHint)
+			return
+		}
+		if elt, ok := ts.Elt.(*ast.Ident); ok && predeclaredShape(elt.Name) == "uint8" {
+			v.report(ts.Pos(), path, "unsupported type %s: json/v2 encodes byte slices as base64 strings (%s, or use []int)", exprToString(ts), ignoreHint)
+			return
+		}
+		v.walk(ts.Elt, path+"[]")
+	case *ast.MapType:
+		if kt, ok := ts.Key.(*ast.Ident); !ok || kt.Name != "string" {
+			v.report(ts.Key.Pos(), path, "unsupported map key type %s (%s, or use string keys)", exprToString(ts.Key), ignoreHint)
+			return
+		}
+		v.walk(ts.Value, path+"{}")
+	case *ast.StructType:
+		for _, field := range ts.Fields.List {
+			v.field(field, path)
+		}
+		v.duplicates(ts, path)
+	case *ast.ChanType:
+		v.report(ts.Pos(), path, "unsupported type %s: channels cannot be represented in JSON (%s)", exprToString(ts), ignoreHint)
+	case *ast.FuncType:
+		v.report(ts.Pos(), path, "unsupported type %s: functions cannot be represented in JSON (%s)", exprToString(ts), ignoreHint)
+	case *ast.InterfaceType:
+		if len(ts.Methods.List) == 0 {
+			v.report(ts.Pos(), path, "unsupported type %s (use any)", exprToString(ts))
+		} else {
+			v.report(ts.Pos(), path, "unsupported interface type %s (%s, or use any)", exprToString(ts), ignoreHint)
+		}
+	default:
+		v.report(expr.Pos(), path, "unsupported type %s (%s)", exprToString(expr), ignoreHint)
+	}
+}
+
+func (v *validator) field(field *ast.Field, path string) {
+	if _, err := jsonTagValue(field); err != nil {
+		v.report(field.Tag.Pos(), path, "malformed json tag %s: %v", field.Tag.Value, err)
+		return
+	}
+	jsonTag, jsonOpts := parseTag(field)
+	if jsonTag == "-" {
+		return
+	}
+	for _, opt := range jsonOpts {
+		if opt == "string" {
+			// Ignoring it would encode numbers differently than json/v2
+			v.report(field.Tag.Pos(), fieldPath(path, field), "json tag option \"string\" is not supported: numbers are not quoted (remove the option, or %s)", ignoreHint)
+			return
+		}
+		if !knownOption(opt) {
+			v.warn(field.Tag.Pos(), fieldPath(path, field), "unknown json tag option %q is ignored", opt)
+		}
+	}
+	isEmbedded := len(field.Names) == 0
+	isInline := slices.Contains(jsonOpts, "inline")
+	if isEmbedded || isInline {
+		ts, ok := field.Type.(*ast.Ident)
+		if !ok {
+			v.report(field.Type.Pos(), path, "unsupported embedded or inline field type %s (%s)", exprToString(field.Type), ignoreHint)
+			return
+		}
+		if unicode.IsLower(rune(ts.Name[0])) {
+			// Unexported embedded fields are skipped
+			return
+		}
+		typeSpec, ok := v.g.types[ts.Name]
+		if !ok {
+			v.report(ts.Pos(), path, "unsupported external embedded type %s (%s)", ts.Name, ignoreHint)
+			return
+		}
+		if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+			v.report(ts.Pos(), path, "unsupported embedded type %s: only struct types can be embedded (%s)", ts.Name, ignoreHint)
+			return
+		}
+		v.walk(ts, path+"."+ts.Name)
+		return
+	}
+	for _, name := range field.Names {
+		if !name.IsExported() {
+			// Unexported fields are ignored, like by json/v2
+			continue
+		}
+		if c := caseOption(jsonOpts); c != "" && c != "ignore" && c != "strict" {
+			v.report(field.Tag.Pos(), path+"."+name.Name, "invalid case option %q (use case:ignore or case:strict)", c)
+			continue
+		}
+		if slices.Contains(jsonOpts, "format:number") && !v.g.isString(field.Type) {
+			v.report(field.Type.Pos(), path+"."+name.Name, "format:number is only supported for string fields")
+			continue
+		}
+		v.walk(field.Type, path+"."+name.Name)
+	}
+}
+
+// duplicates reports the fields of struct type ts named in JSON like a
+// previous field of the struct, which json/v2 rejects. Fields of embedded
+// structs are not reported, since the shallowest field shadows the others.
+func (v *validator) duplicates(ts *ast.StructType, path string) {
+	used := make(map[string]string) // JSON names to the fields named so
+	for _, field := range ts.Fields.List {
+		if _, err := jsonTagValue(field); err != nil {
+			continue
+		}
+		jsonTag, jsonOpts := parseTag(field)
+		if jsonTag == "-" || len(field.Names) == 0 || slices.Contains(jsonOpts, "inline") {
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			jsonName := cmp.Or(jsonTag, name.Name)
+			if other, ok := used[jsonName]; ok {
+				v.report(name.Pos(), path+"."+name.Name, "JSON name %q is already used by field %s (rename one of them in its json tag)", jsonName, other)
+				continue
+			}
+			used[jsonName] = name.Name
+		}
+	}
+}
+
+// knownOption reports whether the json tag option opt is supported by the
+// generator.
+func knownOption(opt string) bool {
+	switch opt {
+	case "inline", "omitempty", "omitzero", "required", "format:number":
+		return true
+	}
+	return strings.HasPrefix(opt, "case:")
+}
+
+// fieldPath returns the path of field in the struct at path, the path of the
+// struct itself for embedded fields.
+func fieldPath(path string, field *ast.Field) string {
+	if len(field.Names) == 0 {
+		return path
+	}
+	return path + "." + field.Names[0].Name
+}
+
+// predeclaredShape returns the predeclared type the alias name stands for,
+// uint8 for byte and int32 for rune, or name.
+func predeclaredShape(name string) string {
+	switch name {
+	case "byte":
+		return "uint8"
+	case "rune":
+		return "int32"
+	}
+	return name
+}
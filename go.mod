@@ -1,5 +1,12 @@
 module github.com/paskozdilar/go-gen-json
 
-go 1.25.1
+// encoding/json/v2 and encoding/json/jsontext, which generated code uses, are
+// part of the Go 1.27 API: go vet rejects them in modules for earlier versions.
+go 1.27
 
-require github.com/go-json-experiment/json v0.0.0-20250910080747-cc2cfa0554c3
+require golang.org/x/tools v0.47.0
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
@@ -1,523 +1,188 @@
+// Command go-gen-json generates JSON encoding and decoding methods for Go
+// types. It is a thin wrapper over package gen.
 package main
 
 import (
-	"bytes"
+	"context"
+	"encoding/json/v2"
+	"errors"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/printer"
 	"go/token"
 	"log"
 	"maps"
-	"math"
 	"os"
-	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
-	"unicode"
-)
 
-var debug bool
+	"github.com/paskozdilar/go-gen-json/gen"
+)
 
 func main() {
-	typeName := ParseArgs()
-	fileSpec, types, typeSpec := ParseType(typeName)
-	GenerateUnmarshaler(fileSpec, types, typeSpec)
-	// GenerateMarshaler(fileName, typeSpec)
-}
-
-func ParseArgs() (typeName string) {
-	flag.StringVar(&typeName, "type", "", "Type name to parse")
-	flag.BoolVar(&debug, "debug", false, "Output debug code")
-	flag.Parse()
-	if typeName == "" {
-		flag.Usage()
-	}
-	return typeName
-}
-
-// ParseType searches for type declaration in current directory
-func ParseType(typeName string) (fileSpec *ast.File, types map[string]*ast.TypeSpec, typeSpec *ast.TypeSpec) {
-	// Find Go files in the current directory
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatalf("get current directory: %v", err)
-	}
-	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
-	if err != nil {
-		log.Fatalf("find *.go files: %v", err)
-	}
-	// Parse Go files
-	fset := token.NewFileSet()
-	types = make(map[string]*ast.TypeSpec)
-	found := false
-	for _, file := range files {
-		node, err := parser.ParseFile(fset, file, nil, parser.AllErrors)
-		if err != nil {
-			continue
-		}
-		// Inspect declarations
-		for _, decl := range node.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok {
-				continue
-			}
-			if genDecl.Tok != token.TYPE {
-				continue
-			}
-			for _, spec := range genDecl.Specs {
-				ts, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-				if ts.Assign != token.NoPos {
-					// TODO: handle type aliases
-					continue
-				}
-				types[ts.Name.Name] = ts
-				if ts.Name.Name == typeName {
-					fileSpec, typeSpec = node, ts
-					found = true
-				}
+	cfg, check, jsonOutput := ParseArgs()
+	var diags gen.Diagnostics
+	status := 0
+	if check {
+		diags, status = checkFiles(cfg, !jsonOutput)
+	} else {
+		files, err := gen.Generate(context.Background(), cfg)
+		diags = diagnostics(err)
+		for _, path := range slices.Sorted(maps.Keys(files)) {
+			if err := os.WriteFile(path, files[path], 0o644); err != nil {
+				diags = append(diags, gen.Diagnostic{Message: fmt.Sprintf("write file: %v", err)})
 			}
 		}
 	}
-	if !found {
-		log.Fatalf("type %v not found", typeName)
+	if jsonOutput {
+		printJSON(diags)
+	} else {
+		for _, d := range diags {
+			fmt.Fprintln(os.Stderr, d)
+		}
 	}
-	return fileSpec, types, typeSpec
-}
-
-func GenerateUnmarshaler(fileSpec *ast.File, types map[string]*ast.TypeSpec, typeSpec *ast.TypeSpec) {
-	g := NewGenerator(fileSpec, types)
-	g.GenerateUnmarshalJSON(typeSpec.Name.Name, typeSpec.Type)
-}
-
-type generator struct {
-	name    string                   // package name
-	imports map[string]bool          // set of imports
-	body    bytes.Buffer             // UnmarshalJSONFrom function body
-	types   map[string]*ast.TypeSpec // map of package types
-	lvl     int                      // indent level
-}
-
-func NewGenerator(fileSpec *ast.File, types map[string]*ast.TypeSpec) *generator {
-	return &generator{
-		name:    fileSpec.Name.Name,
-		imports: make(map[string]bool),
-		types:   types,
+	if diags.HasErrors() {
+		status = 1
 	}
+	os.Exit(status)
 }
 
-func (g *generator) GenerateUnmarshalJSON(typeName string, typeExpr ast.Expr) {
-	if debug {
-		g.useImports("log")
+// diagnostics returns the diagnostics of an error returned by package gen,
+// which is a single diagnostic if it is not Diagnostics.
+func diagnostics(err error) gen.Diagnostics {
+	var diags gen.Diagnostics
+	if err == nil || errors.As(err, &diags) {
+		return diags
 	}
-	g.useImports("bytes", "encoding/json/jsontext")
-	g.writeMultiline(fmt.Sprintf(`
-		func (p *%[1]s) UnmarshalJSON(b []byte) error {
-			d := jsontext.NewDecoder(bytes.NewReader(b))
-			return p.UnmarshalJSONFrom(d)
-		}
-
-		func (p *%[1]s) UnmarshalJSONFrom(d *jsontext.Decoder) error {
-			var (
-				t   jsontext.Token
-				err error
-			)
-	`, typeName))
-	g.indent()
-	g.unmarshaler(typeName, typeExpr, "*p", typeName)
-	g.writeLine("return nil")
-	g.unindent()
-	g.writeLine("}")
-	g.flushTo(strings.ToLower(typeName) + "_gen_json.go")
+	return gen.Diagnostics{{Message: err.Error()}}
 }
 
-func (g *generator) writeMultiline(s string) {
-	lines := strings.Split(s, "\n")
-	if lines[0] == "" {
-		lines = lines[1:]
-	}
-	if strings.TrimSpace(lines[len(lines)-1]) == "" {
-		lines = lines[:len(lines)-1]
-	}
-	minIndent := math.MaxInt
-	for _, line := range lines {
-		if line == "" {
+// checkFiles checks the generated files, and returns the diagnostics and the
+// exit status: 1 if any file is not up to date. With printDiffs, each such
+// file is reported with a unified diff; otherwise with an error diagnostic.
+func checkFiles(cfg gen.Config, printDiffs bool) (gen.Diagnostics, int) {
+	diffs, err := gen.Check(context.Background(), cfg)
+	diags := diagnostics(err)
+	for _, d := range diffs {
+		if !printDiffs {
+			diags = append(diags, gen.Diagnostic{Pos: token.Position{Filename: d.Path}, Message: "file is " + d.Reason})
 			continue
 		}
-		indent := len(line) - len(strings.TrimLeft(line, "\t"))
-		minIndent = min(indent, minIndent)
-	}
-	for i, line := range lines {
-		if line != "" {
-			lines[i] = line[minIndent:]
-		}
+		fmt.Fprintf(os.Stderr, "%s is %s\n", d.Path, d.Reason)
+		fmt.Print(d.Diff)
 	}
-	for _, line := range lines {
-		g.writeLine(line)
+	if len(diffs) > 0 {
+		return diags, 1
 	}
+	return diags, 0
 }
 
-func (g *generator) writeLine(line string) {
-	ident := strings.Repeat("\t", g.lvl)
-	output := ident + line + "\n"
-	g.body.Write([]byte(output))
+// jsonDiagnostic is a diagnostic in the output of -json.
+type jsonDiagnostic struct {
+	File     string       `json:"file,omitempty"`
+	Line     int          `json:"line,omitzero"`
+	Column   int          `json:"column,omitzero"`
+	Severity gen.Severity `json:"severity"`
+	Message  string       `json:"message"`
 }
 
-func (g *generator) indent() {
-	g.lvl++
-}
-
-func (g *generator) unindent() {
-	g.lvl--
-}
-
-func (g *generator) useImports(imports ...string) {
-	for _, imp := range imports {
-		g.imports[imp] = true
+// printJSON prints the diagnostics to the standard output as a JSON array,
+// empty if there are none.
+func printJSON(diags gen.Diagnostics) {
+	out := make([]jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		out[i] = jsonDiagnostic{d.Pos.Filename, d.Pos.Line, d.Pos.Column, d.Severity, d.Message}
 	}
-}
-
-func (g *generator) flushTo(fileName string) {
-	// Create file
-	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	b, err := json.Marshal(out)
 	if err != nil {
-		log.Fatalf("create file %s: %v", fileName, err)
-	}
-	defer f.Close()
-	// Header
-	fmt.Fprintf(f, "// Code generated by go-gen-json. DO NOT EDIT.\n")
-	fmt.Fprintf(f, "package %s\n\n", g.name)
-	// Imports
-	imports := slices.Collect(maps.Keys(g.imports))
-	if len(imports) == 1 {
-		fmt.Fprintf(f, "import \"%s\"\n\n", imports[0])
-	} else if len(imports) > 1 {
-		slices.Sort(imports)
-		fmt.Fprintf(f, "import (\n")
-		for _, imp := range imports {
-			fmt.Fprintf(f, "\t\"%s\"\n", imp)
-		}
-		fmt.Fprintf(f, ")\n\n")
-	}
-	// Body
-	g.body.WriteTo(f)
-}
-
-func (g *generator) unmarshaler(typeName string, typeExpr ast.Expr, varExpr string, originalName string) {
-	if debug {
-		log.Printf("- unmarshaler: %s", typeName)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler: %s")`, typeName))
-	}
-	switch ts := typeExpr.(type) {
-	case *ast.Ident:
-		g.unmarshalerIdent(ts.Name, varExpr, typeName)
-	case *ast.SelectorExpr:
-		g.unmarshalerSelector(typeName, ts, varExpr)
-	case *ast.StructType:
-		g.unmarshalerStruct(typeName, ts, varExpr)
-	case *ast.ArrayType:
-		g.unmarshalerArray(ts.Elt, varExpr)
-	case *ast.MapType:
-		g.unmarshalerMap(ts.Key, ts.Value, varExpr)
-	case *ast.StarExpr:
-		g.unmarshalerPointer(typeName, ts, varExpr, originalName)
-	default:
-		log.Fatalf("not implemented for type: %T", ts)
-	}
-}
-
-func (g *generator) unmarshalerIdent(typeName string, varExpr string, targetTypeName string) {
-	if debug {
-		log.Printf("- unmarshaler ident: %s [%s]", typeName, targetTypeName)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler ident: %s [%s]")`, typeName, targetTypeName))
-	}
-	switch typeName {
-	case "string":
-		g.useImports("errors")
-		g.writeMultiline(fmt.Sprintf(`
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			} 
-			if t.Kind() != '"' {
-				return errors.New("expected string, got " + string(t.Kind()))
-			}
-			%s = %s(t.String())
-		`, varExpr, targetTypeName))
-	case "int", "int32", "int64":
-		g.useImports("errors")
-		g.writeMultiline(fmt.Sprintf(`
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != '0' {
-				return errors.New("expected number, got " + string(t.Kind()))
-			}
-			%s = %s(t.Int())
-		`, varExpr, targetTypeName))
-	case "bool":
-		g.useImports("errors")
-		g.writeMultiline(fmt.Sprintf(`
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != 't' && t.Kind() != 'f' {
-				return errors.New("expected bool, got " + string(t.Kind()))
-			}
-			%s = t.Kind() == 't'`, varExpr))
-	case "float32", "float64":
-		g.useImports("errors")
-		g.writeMultiline(fmt.Sprintf(`
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != '0' {
-				return errors.New("expected number, got " + string(t.Kind()))
-			}
-			%s = %s(t.Float())
-		`, varExpr, targetTypeName))
-	case "any":
-		g.useImports("encoding/json/v2")
-		g.writeMultiline(fmt.Sprintf(`
-			// TODO: optimize this?
-			if v, err := d.ReadValue(); err != nil {
-				return err
-			} else if err := json.Unmarshal(v, &%s); err != nil {
-				return nil
+		log.Fatalf("encode diagnostics: %v", err)
+	}
+	fmt.Printf("%s\n", b)
+}
+
+// ParseArgs parses the flags into the configuration of the generator: the
+// types to generate (none for the marked types) and the patterns of the
+// packages declaring them (the arguments, "." by default). It also reports
+// whether to check the generated files instead of writing them, with -check
+// or with GOGENJSON_CHECK=1 in the environment, and whether to print the
+// diagnostics as JSON. The option flags set on the command line override the
+// configuration files.
+func ParseArgs() (gen.Config, bool, bool) {
+	var (
+		cfg      gen.Config
+		typeList string
+	)
+	flag.StringVar(&typeList, "type", "", "Comma-separated names of the types to generate methods for")
+	flag.BoolVar(&cfg.All, "all", false, "Generate methods for every exported struct type of the packages")
+	flag.StringVar(&cfg.Tags, "tags", "", "Comma-separated build tags to load the packages with")
+	flag.StringVar(&cfg.Output, "output", "", "Write the methods of all types of a package to this file in its directory, or of each type to this file with {type} replaced by its name, instead of <type>_gen_json.go")
+	check := flag.Bool("check", os.Getenv("GOGENJSON_CHECK") == "1", "Exit with status 1 and a diff if any generated file is stale, missing or orphaned, instead of writing them")
+	jsonOutput := flag.Bool("json", false, "Print the diagnostics to the standard output as a JSON array, and report files failing -check as diagnostics instead of diffs")
+	flag.BoolVar(&cfg.NoConfigFile, "noconfig", false, "Ignore gen-json.toml and gen-json.json configuration files")
+	bindOptions(flag.CommandLine, &cfg.Options)
+	flag.Parse()
+	if typeList != "" {
+		cfg.Types = strings.Split(typeList, ",")
+	}
+	cfg.Patterns = flag.Args()
+	cfg.Override = func(opts *gen.Options) {
+		// Set the flags set on the command line again, over the options of
+		// the configuration file
+		fs := flag.NewFlagSet("", flag.PanicOnError)
+		bindOptions(fs, opts)
+		flag.Visit(func(f *flag.Flag) {
+			if fs.Lookup(f.Name) != nil {
+				fs.Set(f.Name, f.Value.String())
 			}
-		`, varExpr))
-	default:
-		if typeSpec, ok := g.types[typeName]; ok {
-			g.unmarshaler(typeSpec.Name.Name, typeSpec.Type, varExpr, targetTypeName)
-		} else {
-			log.Fatalf("unrecognized type: %s", typeName)
-		}
+		})
 	}
+	return cfg, *check, *jsonOutput
 }
 
-func (g *generator) unmarshalerSelector(typeName string, expr *ast.SelectorExpr, varExpr string) {
-	if debug {
-		log.Printf("- unmarshaler selector: %s (%s)", typeName, varExpr)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler selector: %s (%s)")`, typeName, varExpr))
-	}
-	X, ok := expr.X.(*ast.Ident)
-	if !ok {
-		log.Fatalf("go-gen-json does not support non-Time selector expr")
-	}
-	if X.Name != "time" || expr.Sel.Name != "Time" {
-		log.Fatalf("go-gen-json does not support external packages")
-	}
-	g.useImports("errors")
-	g.writeMultiline(fmt.Sprintf(`
-		t, err = d.ReadToken()
-		if err != nil {
-			return nil
-		}
-		if err = %s.UnmarshalText([]byte(t.String())); err != nil {
-			return err
-		}
-	`, varExpr))
+// bindOptions defines the flags of the options opts in fs, defaulting to the
+// current options.
+func bindOptions(fs *flag.FlagSet, opts *gen.Options) {
+	fs.BoolVar(&opts.Debug, "debug", opts.Debug, "Output debug code")
+	fs.BoolVar(&opts.UseNumber, "usenumber", opts.UseNumber, "Decode numbers in any values as json.Number")
+	fs.BoolVar(&opts.RejectUnknown, "rejectunknown", opts.RejectUnknown, "Reject unknown object members regardless of json.RejectUnknownMembers")
+	fs.BoolVar(&opts.RejectDuplicates, "rejectduplicates", opts.RejectDuplicates, "Reject duplicate object member names regardless of jsontext.AllowDuplicateNames")
+	fs.BoolVar(&opts.Collect, "collect", opts.Collect, "Generate UnmarshalJSONCollect, reporting all semantic errors at once")
+	fs.Var(strictFlag{opts}, "strict", "Reject unknown object members and duplicate names (-rejectunknown -rejectduplicates)")
+	fs.BoolVar(&opts.Deterministic, "deterministic", opts.Deterministic, "Encode maps in sorted key order regardless of json.Deterministic")
+	fs.BoolVar(&opts.MarshalOnly, "marshalonly", opts.MarshalOnly, "Generate the encoding methods only")
+	fs.BoolVar(&opts.IgnoreCase, "ignorecase", opts.IgnoreCase, "Match member names case-insensitively like encoding/json v1 (fields tagged case:strict excepted)")
+	fs.BoolVar(&opts.Limits, "limits", opts.Limits, "Generate UnmarshalJSONLimits and enforce limits, unlimited unless set with the -max flags")
+	fs.BoolVar(&opts.Fingerprint, "fingerprint", opts.Fingerprint, "Embed the fingerprint of each type, and panic in init if the type has changed since")
+	fs.BoolVar(&opts.Fallback, "fallback", opts.Fallback, "Build the generated files unless the nogenjson tag is set, and generate <file>_fallback.go using json/v2 reflection for when it is")
+	fs.StringVar(&opts.NilSlice, "nilslice", opts.NilSlice, "Encode nil slices as null or empty regardless of json.FormatNilSliceAsNull")
+	fs.StringVar(&opts.TimeFormat, "timeformat", opts.TimeFormat, "Encode and decode time.Time with this layout or time constant name (e.g. DateOnly) instead of RFC 3339")
+	fs.IntVar(&opts.MaxDepth, "maxdepth", opts.MaxDepth, "Limit the nesting depth of objects and arrays (enables -limits)")
+	fs.IntVar(&opts.MaxBytes, "maxbytes", opts.MaxBytes, "Limit the size of the input in bytes (enables -limits)")
+	fs.IntVar(&opts.MaxElements, "maxelements", opts.MaxElements, "Limit the number of elements per array (enables -limits)")
+	fs.IntVar(&opts.MaxMembers, "maxmembers", opts.MaxMembers, "Limit the number of members per object (enables -limits)")
+	fs.IntVar(&opts.MaxStringLength, "maxstring", opts.MaxStringLength, "Limit the length of strings and member names in bytes (enables -limits)")
 }
 
-func (g *generator) unmarshalerStruct(typeName string, ts *ast.StructType, varExpr string) {
-	if debug {
-		log.Printf("- unmarshaler struct: %s", typeName)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler struct: %s")`, typeName))
-	}
-	g.useImports("errors")
-	g.writeMultiline(`
-		t, err = d.ReadToken()
-		if err != nil {
-			return err
-		}
-		if t.Kind() != '{' {
-			return errors.New("expected object start, got " + string(t.Kind()))
-		}
-		for d.PeekKind() != '}' {
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != '"' {
-				return errors.New("expected string, got " + string(t.Kind()))
-			}
-			switch t.String() {
-	`)
-	g.indent()
-	for _, field := range ts.Fields.List {
-		g.unmarshalerField(field, varExpr)
-	}
-	g.unindent()
-	g.writeMultiline(`
-			default:
-				d.SkipValue()
-			}
-		}
-		_, _ = d.ReadToken()
-	`)
+// strictFlag is the -strict flag of opts, setting both RejectUnknown and
+// RejectDuplicates.
+type strictFlag struct {
+	opts *gen.Options
 }
 
-func (g *generator) unmarshalerField(field *ast.Field, varExpr string) {
-	var jsonTag string
-	var jsonOpts []string
-	if field.Tag != nil {
-		tag, err := strconv.Unquote(field.Tag.Value)
-		if err != nil {
-			log.Fatalf("parse json tag: %v", err)
-		}
-		parts := strings.Split(tag, " ")
-		idx := slices.IndexFunc(parts, func(part string) bool {
-			return strings.HasPrefix(part, "json:")
-		})
-		if idx != -1 {
-			sTags, err := strconv.Unquote(strings.TrimPrefix(parts[idx], "json:"))
-			if err != nil {
-				log.Fatalf("parse json tag: %v", err)
-			}
-			tags := strings.Split(sTags, ",")
-			jsonTag = tags[0]
-			if jsonTag == "-" {
-				// Skip this field
-				return
-			}
-			if len(tags) > 1 {
-				jsonOpts = strings.Split(sTags, ",")[1:]
-			}
-		}
-	}
-	isEmbedded := len(field.Names) == 0
-	isInline := slices.Contains(jsonOpts, "inline")
-	if isEmbedded || isInline {
-		// Embedded or inline: recurse if struct
-		switch ts := field.Type.(type) {
-		case *ast.Ident:
-			if unicode.IsLower(rune(ts.Name[0])) {
-				// Skip unexported embedded field
-				return
-			}
-			typeSpec, ok := g.types[ts.Name]
-			if !ok {
-				log.Fatalf("go-gen-json does not support external embedded types: %s", ts.Name)
-			}
-			st, ok := typeSpec.Type.(*ast.StructType)
-			if !ok {
-				log.Fatalf("go-gen-json only supports embedded struct types: %s", ts.Name)
-			}
-			for _, f := range st.Fields.List {
-				g.unmarshalerField(f, fmt.Sprintf("(%s).%s", varExpr, ts.Name))
-			}
-		// TODO:
-		// case *ast.SelectorExpr:
-		// case *ast.StarExpr:
-		// case *ast.StructType:
-		default:
-			log.Fatalf("unsupported embedded or inline field type: %T", field.Type)
-		}
-		return
-	}
-	for _, name := range field.Names {
-		if jsonTag == "" {
-			jsonTag = name.Name
-		}
-		typeString := exprToString(field.Type)
-		g.writeLine(fmt.Sprintf(`case "%s":`, jsonTag))
-		g.indent()
-		g.unmarshaler(typeString, field.Type, fmt.Sprintf("(%s).%s", varExpr, name.Name), typeString)
-		g.unindent()
-	}
-}
-
-func (g *generator) unmarshalerArray(elemType ast.Expr, varExpr string) {
-	if debug {
-		log.Printf("- unmarshaler array: %s", varExpr)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler array: %s")`, varExpr))
-	}
-	typeString := exprToString(elemType)
-	g.useImports("errors")
-	g.writeMultiline(fmt.Sprintf(`
-		t, err = d.ReadToken()
-		if err != nil {
-			return err
-		}
-		if t.Kind() != '[' {
-			return errors.New("expected array start, got " + string(t.Kind()))
-		}
-		%s = nil
-		for d.PeekKind() != ']' {
-			var elem %s
-	`, varExpr, typeString))
-	g.indent()
-	g.unmarshaler(typeString, elemType, "elem", typeString)
-	g.unindent()
-	g.writeMultiline(fmt.Sprintf(`
-			%s = append(%s, elem)
-		}
-		_, _ = d.ReadToken()
-	`, varExpr, varExpr))
-}
+func (f strictFlag) IsBoolFlag() bool { return true }
 
-func (g *generator) unmarshalerMap(keyType ast.Expr, valueType ast.Expr, varExpr string) {
-	if kt, ok := keyType.(*ast.Ident); !ok || kt.Name != "string" {
-		log.Fatalf("JSON does not support non-string map keys")
+func (f strictFlag) String() string {
+	if f.opts == nil {
+		return "false"
 	}
-	g.useImports("errors")
-	valueTypeName := exprToString(valueType)
-	g.writeMultiline(fmt.Sprintf(`
-		t, err = d.ReadToken()
-		if err != nil {
-			return err
-		}
-		if t.Kind() != '{' {
-			return errors.New("expected object start, got " + string(t.Kind()))
-		}
-		%s = make(map[string]%s)
-		for d.PeekKind() != '}' {
-			t, err = d.ReadToken()
-			if err != nil {
-				return err
-			}
-			if t.Kind() != '"' {
-				return errors.New("expected string, got " + string(t.Kind()))
-			}
-			key := t.String()
-			var value %[2]s
-	`, varExpr, valueTypeName))
-	g.indent()
-	g.unmarshaler(valueTypeName, valueType, "value", valueTypeName)
-	g.unindent()
-	g.writeMultiline(fmt.Sprintf(`
-			%s[key] = value
-		}
-		_, _ = d.ReadToken()
-	`, varExpr))
+	return strconv.FormatBool(f.opts.RejectUnknown && f.opts.RejectDuplicates)
 }
 
-func (g *generator) unmarshalerPointer(typeName string, ts *ast.StarExpr, varExpr string, originalName string) {
-	if debug {
-		log.Printf("- unmarshaler pointer: %s (%s)", typeName, varExpr)
-		g.writeLine(fmt.Sprintf(`log.Println("- unmarshaler pointer: %s (%s)")`, typeName, varExpr))
+func (f strictFlag) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
 	}
-	// Don't read anything: just initialize varExpr pointer, then call unmarshaler on the underlying type
-	g.useImports("errors")
-	g.writeMultiline(fmt.Sprintf(`
-		if %[1]s == nil {
-			%[1]s = new(%[2]s)
-		}
-	`, varExpr, exprToString(ts.X)))
-	g.unmarshaler(originalName, ts.X, fmt.Sprintf("(*%s)", varExpr), originalName)
-}
-
-// Hacky way to get type string
-func exprToString(expr ast.Expr) string {
-	buf := bytes.Buffer{}
-	printer.Fprint(&buf, token.NewFileSet(), expr)
-	return buf.String()
+	f.opts.RejectUnknown, f.opts.RejectDuplicates = on, on
+	return nil
 }